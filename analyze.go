@@ -0,0 +1,109 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "text/tabwriter"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/transfer"
+)
+
+// runAnalyze implements "analyze [flags] [VALUE...]". Each argument is
+// analyzed on its own; without arguments standard input is read as one
+// value, or as one value per line with -lines. Nothing is stored, so it
+// does not touch the database.
+func runAnalyze(args []string) error {
+    flags := newFlagSet("analyze", "[VALUE...]")
+    format := flags.String("format", "json", "json (one object per line) or table")
+    lines := flags.Bool("lines", false, "analyze every non-blank line of standard input separately")
+    palindromeMode := flags.String("palindrome-mode", "", "palindrome normalization, as palindrome_mode on POST /strings")
+    hashes := flags.String("hashes", "", "extra digests to compute, comma-separated")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if err := checkOutputFormat(*format); err != nil {
+        return err
+    }
+    
+    var opts services.Options
+    var err error
+    if opts.PalindromeMode, err = services.ParsePalindromeMode(*palindromeMode); err != nil {
+        return err
+    }
+    if opts.Hashes, err = services.ParseHashes(*hashes); err != nil {
+        return err
+    }
+    cfg, _, err := loadConfig()
+    if err != nil {
+        return err
+    }
+    opts.IDHash = cfg.Analysis.PrimaryIDHash
+    
+    values := flags.Args()
+    if len(values) == 0 {
+        if values, err = readInput(os.Stdin, *lines); err != nil {
+            return err
+        }
+    }
+    
+    results := make([]models.AnalysisResult, len(values))
+    for i, value := range values {
+        if results[i], err = services.AnalyzeStringWithOptions(value, opts); err != nil {
+            return err
+        }
+    }
+    if *format == "table" {
+        return writeResultTable(os.Stdout, results)
+    }
+    encoder := json.NewEncoder(os.Stdout)
+    for _, result := range results {
+        if err := encoder.Encode(result); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// readInput reads standard input as a single value without its final line
+// ending, or as its non-blank lines
+func readInput(r io.Reader, lines bool) ([]string, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    text := string(data)
+    if !lines {
+        return []string{strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")}, nil
+    }
+    var values []string
+    for _, line := range strings.Split(text, "\n") {
+        line = strings.TrimSuffix(line, "\r")
+        if strings.TrimSpace(line) != "" {
+            values = append(values, line)
+        }
+    }
+    return values, nil
+}
+
+// writeResultTable prints one property per row, in the column order of the
+// CSV export, with a blank line between results
+func writeResultTable(w io.Writer, results []models.AnalysisResult) error {
+    table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+    for i := range results {
+        if i > 0 {
+            fmt.Fprintln(table)
+        }
+        for _, column := range transfer.Columns {
+            text, err := column.Text(&results[i])
+            if err != nil {
+                return err
+            }
+            fmt.Fprintf(table, "%s\t%s\n", column.Name, text)
+        }
+    }
+    return table.Flush()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "github.com/holladworld/string-analyzer/config"
+    "github.com/holladworld/string-analyzer/handlers"
+    "github.com/holladworld/string-analyzer/storage"
+)
+
+// command is a subcommand of the string-analyzer binary
+type command struct {
+    name    string
+    summary string
+    run     func(args []string) error
+}
+
+// commands is filled in by init because usage refers back to it
+var commands []command
+
+func init() {
+    commands = []command{
+        {"serve", "Run the HTTP API (the default without a command)", runServe},
+        {"analyze", "Analyze values from the arguments or standard input without storing them", runAnalyze},
+        {"import", "Store the values of a text, CSV or JSON Lines file", runImport},
+        {"export", "Write stored strings as JSON Lines, CSV or Parquet", runExport},
+        {"stats", "Summarize stored strings", runStats},
+        {"delete", "Remove stored strings by value, ID or filter", runDelete},
+        {"migrate", "Apply, revert or list schema migrations", runMigrate},
+    }
+}
+
+// runCommand dispatches to the subcommand named by args[0]. Without
+// arguments it serves, so existing deployments keep working unchanged.
+func runCommand(args []string) error {
+    if len(args) == 0 {
+        return run()
+    }
+    switch args[0] {
+    case "help", "-h", "-help", "--help":
+        usage(os.Stdout)
+        return nil
+    }
+    for _, cmd := range commands {
+        if cmd.name == args[0] {
+            return cmd.run(args[1:])
+        }
+    }
+    usage(os.Stderr)
+    return fmt.Errorf("unknown command %q", args[0])
+}
+
+func usage(w io.Writer) {
+    fmt.Fprintln(w, "Usage: string-analyzer [COMMAND] [flags]")
+    fmt.Fprintln(w, "\nCommands:")
+    for _, cmd := range commands {
+        fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+    }
+    fmt.Fprintln(w, "\nRun \"string-analyzer COMMAND -h\" for the flags of a command. Every command reads")
+    fmt.Fprintln(w, "the same environment variables and CONFIG_FILE as the server.")
+}
+
+// newFlagSet returns the flags of a subcommand; operands describes its
+// positional arguments in the usage line
+func newFlagSet(name, operands string) *flag.FlagSet {
+    flags := flag.NewFlagSet(name, flag.ContinueOnError)
+    flags.Usage = func() {
+        fmt.Fprintf(flags.Output(), "Usage: string-analyzer %s [flags] %s\n", name, operands)
+        flags.PrintDefaults()
+    }
+    return flags
+}
+
+// runServe is the serve subcommand, which takes no arguments
+func runServe(args []string) error {
+    flags := newFlagSet("serve", "")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if flags.NArg() != 0 {
+        flags.Usage()
+        return errors.New("serve takes no arguments")
+    }
+    return run()
+}
+
+// openStore opens the configured storage for the subcommands that work on
+// stored strings. The memory backend is refused: it would start empty and
+// forget every change on exit.
+func openStore(cfg config.Config) (storage.StringRepository, error) {
+    if cfg.StorageBackend == "memory" {
+        return nil, errors.New("this command needs persistent storage, but STORAGE_BACKEND is memory")
+    }
+    repo, err := newRepository(cfg)
+    if err != nil {
+        return nil, errors.New("Failed to initialize storage: " + err.Error())
+    }
+    return repo, nil
+}
+
+// filterFlag adds the -filter flag shared by export, stats and delete
+func filterFlag(flags *flag.FlagSet, usage string) *string {
+    return flags.String("filter", "", usage+" matching these GET /strings filters, e.g. is_palindrome=true&min_length=3")
+}
+
+// outputFlag adds the -format flag of the commands that print a report
+func outputFlag(flags *flag.FlagSet) *string {
+    return flags.String("format", "json", "json or table")
+}
+
+func checkOutputFormat(format string) error {
+    if format != "json" && format != "table" {
+        return fmt.Errorf("unknown format '%s' (allowed: json, table)", format)
+    }
+    return nil
+}
+
+// parseFilterFlag reads a -filter value; an empty one matches everything
+func parseFilterFlag(query string) (storage.Filters, error) {
+    if query == "" {
+        return storage.Filters{}, nil
+    }
+    return handlers.ParseFilterQuery(query)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+    "bytes"
+    "compress/gzip"
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "flag"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "github.com/holladworld/string-analyzer/transfer"
+)
+
+// useTempStore points every command at a fresh SQLite file, so that tests
+// never touch a real database, and returns its directory
+func useTempStore(t *testing.T) string {
+    t.Helper()
+    dir := t.TempDir()
+    for _, key := range []string{"CONFIG_FILE", "DB_DSN", "ANALYZERS_DISABLED", "SECRET_POLICY", "PRIMARY_ID_HASH"} {
+        t.Setenv(key, "")
+    }
+    t.Setenv("STORAGE_BACKEND", "sqlite")
+    t.Setenv("DB_PATH", filepath.Join(dir, "cli.db"))
+    return dir
+}
+
+// runCLI runs a command and returns what it wrote to standard output.
+// Standard error, where usage and flag errors go, is discarded.
+func runCLI(t *testing.T, args ...string) (string, error) {
+    t.Helper()
+    stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+    if err != nil {
+        t.Fatalf("Failed to create a stdout file: %v", err)
+    }
+    defer stdout.Close()
+    stderr, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+    if err != nil {
+        t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+    }
+    defer stderr.Close()
+    
+    savedOut, savedErr := os.Stdout, os.Stderr
+    os.Stdout, os.Stderr = stdout, stderr
+    runErr := runCommand(args)
+    os.Stdout, os.Stderr = savedOut, savedErr
+    
+    data, err := os.ReadFile(stdout.Name())
+    if err != nil {
+        t.Fatalf("Failed to read the output: %v", err)
+    }
+    return string(data), runErr
+}
+
+// TestRunCommandArguments tests dispatch and the argument checks that run
+// before any command opens storage
+func TestRunCommandArguments(t *testing.T) {
+    useTempStore(t)
+    cases := []struct {
+        args []string
+        err  string
+    }{
+        {[]string{"help"}, ""},
+        {[]string{"--help"}, ""},
+        {[]string{"bogus"}, `unknown command "bogus"`},
+        {[]string{"serve", "now"}, "serve takes no arguments"},
+        {[]string{"analyze", "-format", "xml", "x"}, "unknown format 'xml'"},
+        {[]string{"analyze", "-palindrome-mode", "loose", "x"}, "loose"},
+        {[]string{"import"}, "import needs exactly one FILE"},
+        {[]string{"import", "a.txt", "b.txt"}, "import needs exactly one FILE"},
+        {[]string{"import", "-format", "xml", "a.txt"}, "xml"},
+        {[]string{"import", "-on-duplicate", "merge", "a.txt"}, "unknown duplicate policy 'merge'"},
+        {[]string{"import", "-hashes", "sha3", "a.txt"}, "sha3"},
+        {[]string{"export", "out.csv"}, "export takes no arguments"},
+        {[]string{"export", "-format", "xml"}, "xml"},
+        {[]string{"export", "-filter", "min_length=abc"}, "min_length"},
+        {[]string{"stats", "extra"}, "stats takes no arguments"},
+        {[]string{"stats", "-format", "xml"}, "unknown format 'xml'"},
+        {[]string{"delete"}, "delete needs either arguments or -filter"},
+        {[]string{"delete", "-filter", "is_palindrome=true", "level"}, "delete needs either arguments or -filter"},
+        {[]string{"migrate", "sideways"}, "unknown migrate action 'sideways'"},
+        {[]string{"migrate", "down", "0"}, "invalid STEPS '0'"},
+        {[]string{"migrate", "status", "1"}, "too many arguments"},
+        {[]string{"import", "-unknown-flag", "a.txt"}, "flag provided but not defined"},
+    }
+    for _, tc := range cases {
+        _, err := runCLI(t, tc.args...)
+        switch {
+        case tc.err == "" && err != nil:
+            t.Errorf("runCommand(%q) failed: %v", tc.args, err)
+        case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+            t.Errorf("runCommand(%q) = %v, want an error containing %q", tc.args, err, tc.err)
+        }
+    }
+    
+    // -h is reported as flag.ErrHelp, which main exits on quietly
+    for _, name := range []string{"serve", "analyze", "import", "export", "stats", "delete", "migrate"} {
+        if _, err := runCLI(t, name, "-h"); !errors.Is(err, flag.ErrHelp) {
+            t.Errorf("%s -h returned %v, want flag.ErrHelp", name, err)
+        }
+    }
+}
+
+// TestRunCommandMemoryBackend tests that the storage commands refuse the
+// memory backend, which would forget every change on exit
+func TestRunCommandMemoryBackend(t *testing.T) {
+    useTempStore(t)
+    t.Setenv("STORAGE_BACKEND", "memory")
+    for _, args := range [][]string{{"stats"}, {"export"}, {"delete", "x"}, {"migrate"}} {
+        if _, err := runCLI(t, args...); err == nil || !strings.Contains(err.Error(), "STORAGE_BACKEND is memory") {
+            t.Errorf("runCommand(%q) = %v, want the memory backend to be refused", args, err)
+        }
+    }
+}
+
+// TestAnalyzeCommand tests that analyze prints one result per argument
+func TestAnalyzeCommand(t *testing.T) {
+    useTempStore(t)
+    out, err := runCLI(t, "analyze", "racecar", "hello world")
+    if err != nil {
+        t.Fatalf("analyze failed: %v", err)
+    }
+    lines := strings.Split(strings.TrimSpace(out), "\n")
+    if len(lines) != 2 {
+        t.Fatalf("Expected 2 results, got %q", out)
+    }
+    var result struct {
+        Value        string `json:"value"`
+        IsPalindrome bool   `json:"is_palindrome"`
+    }
+    if err := json.Unmarshal([]byte(lines[0]), &result); err != nil || result.Value != "racecar" || !result.IsPalindrome {
+        t.Errorf("Unexpected first result %q (%v)", lines[0], err)
+    }
+    
+    if out, err := runCLI(t, "analyze", "-format", "table", "abc"); err != nil || !strings.Contains(out, "value") || !strings.Contains(out, "abc") {
+        t.Errorf("Unexpected table output %q (%v)", out, err)
+    }
+}
+
+// TestImportExportDelete tests the storage commands against a temporary
+// SQLite file: import, re-import, filtered and compressed export, stats
+// and deletion by value and by filter
+func TestImportExportDelete(t *testing.T) {
+    dir := useTempStore(t)
+    input := filepath.Join(dir, "values.txt")
+    if err := os.WriteFile(input, []byte("racecar\nhello world\nlevel\n"), 0o644); err != nil {
+        t.Fatalf("Failed to write the input: %v", err)
+    }
+    
+    var report transfer.ImportReport
+    out, err := runCLI(t, "import", input)
+    if err != nil || json.Unmarshal([]byte(out), &report) != nil || report.Created != 3 {
+        t.Fatalf("Unexpected import report %q (%v)", out, err)
+    }
+    out, err = runCLI(t, "import", input)
+    if err != nil || json.Unmarshal([]byte(out), &report) != nil || report.Created != 0 || report.Skipped != 3 {
+        t.Fatalf("Expected the re-import to skip every value, got %q (%v)", out, err)
+    }
+    
+    csvPath := filepath.Join(dir, "palindromes.csv")
+    if _, err := runCLI(t, "export", "-o", csvPath, "-filter", "is_palindrome=true"); err != nil {
+        t.Fatalf("export failed: %v", err)
+    }
+    file, err := os.Open(csvPath)
+    if err != nil {
+        t.Fatalf("Failed to open the export: %v", err)
+    }
+    records, err := csv.NewReader(file).ReadAll()
+    file.Close()
+    if err != nil || len(records) != 3 {
+        t.Errorf("Expected a header and 2 palindromes, got %v (%v)", records, err)
+    }
+    
+    gzPath := filepath.Join(dir, "all.jsonl.gz")
+    if _, err := runCLI(t, "export", "-o", gzPath); err != nil {
+        t.Fatalf("export failed: %v", err)
+    }
+    compressed, err := os.ReadFile(gzPath)
+    if err != nil {
+        t.Fatalf("Failed to read the export: %v", err)
+    }
+    gz, err := gzip.NewReader(bytes.NewReader(compressed))
+    if err != nil {
+        t.Fatalf("Invalid gzip export: %v", err)
+    }
+    data, err := io.ReadAll(gz)
+    if err != nil || bytes.Count(data, []byte("\n")) != 3 {
+        t.Errorf("Expected 3 JSON lines, got %q (%v)", data, err)
+    }
+    
+    var deleted deleteReport
+    out, err = runCLI(t, "delete", "hello world", "missing")
+    if err != nil || json.Unmarshal([]byte(out), &deleted) != nil || deleted.Deleted != 1 || len(deleted.NotFound) != 1 || deleted.NotFound[0] != "missing" {
+        t.Errorf("Unexpected delete report %q (%v)", out, err)
+    }
+    out, err = runCLI(t, "delete", "-filter", "is_palindrome=true")
+    if err != nil || json.Unmarshal([]byte(out), &deleted) != nil || deleted.Deleted != 2 {
+        t.Errorf("Expected the filter to delete both palindromes, got %q (%v)", out, err)
+    }
+    
+    var stats struct {
+        Count int `json:"count"`
+    }
+    out, err = runCLI(t, "stats")
+    if err != nil || json.Unmarshal([]byte(out), &stats) != nil || stats.Count != 0 {
+        t.Errorf("Expected an empty store, got %q (%v)", out, err)
+    }
+}
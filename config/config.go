@@ -0,0 +1,360 @@
+package config
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "net/url"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Config is the typed application configuration. Values come from an
+// optional KEY=VALUE file named by CONFIG_FILE (same syntax as .env.example),
+// overridden by environment variables of the same name.
+type Config struct {
+    Port           string
+    StorageBackend string
+    Database       DatabaseConfig
+    Server         ServerConfig
+    Analysis       AnalysisConfig
+    Batch          BatchConfig
+}
+
+// DatabaseConfig controls how the SQLite database is opened
+type DatabaseConfig struct {
+    // Path is the SQLite file; ignored when DSN is set
+    Path string
+    // DSN is passed to the driver verbatim and overrides Path, JournalMode,
+    // BusyTimeout and ForeignKeys
+    DSN             string
+    JournalMode     string
+    BusyTimeout     time.Duration
+    ForeignKeys     bool
+    MaxOpenConns    int
+    MaxIdleConns    int
+    ConnMaxLifetime time.Duration
+}
+
+// ServerConfig holds the HTTP server timeouts
+type ServerConfig struct {
+    ReadTimeout       time.Duration
+    ReadHeaderTimeout time.Duration
+    WriteTimeout      time.Duration
+    IdleTimeout       time.Duration
+    // ShutdownTimeout bounds how long in-flight requests may drain on SIGTERM
+    ShutdownTimeout time.Duration
+    // IdempotencyTTL is how long responses are kept for Idempotency-Key retries
+    IdempotencyTTL time.Duration
+    // MaxBodyBytes caps the JSON bodies of POST /strings, POST /strings/batch
+    // and PUT /strings; streamed imports are not capped
+    MaxBodyBytes int
+}
+
+// AnalysisConfig controls the analyzer registry
+type AnalysisConfig struct {
+    // DisabledAnalyzers names registered analyzers to switch off at startup
+    DisabledAnalyzers []string
+    // SecretPolicy is allow, redact or reject and applies to values in which
+    // the secrets analyzer finds credentials
+    SecretPolicy string
+    // PrimaryIDHash names the digest used as the ID of new strings
+    PrimaryIDHash string
+}
+
+// BatchConfig bounds POST /strings/batch
+type BatchConfig struct {
+    // Workers is how many values are analyzed concurrently per batch
+    Workers int
+    // MaxItems is the largest batch accepted; larger ones get 413
+    MaxItems int
+}
+
+// Default returns the configuration used when nothing is set
+func Default() Config {
+    return Config{
+        Port:           "8080",
+        StorageBackend: "sqlite",
+        Database: DatabaseConfig{
+            Path:         "./string_analyzer.db",
+            JournalMode:  "WAL",
+            BusyTimeout:  5 * time.Second,
+            ForeignKeys:  true,
+            MaxOpenConns: 10,
+            MaxIdleConns: 5,
+        },
+        Server: ServerConfig{
+            ReadTimeout:       15 * time.Second,
+            ReadHeaderTimeout: 5 * time.Second,
+            WriteTimeout:      30 * time.Second,
+            IdleTimeout:       60 * time.Second,
+            ShutdownTimeout:   20 * time.Second,
+            IdempotencyTTL:    24 * time.Hour,
+            MaxBodyBytes:      10 << 20,
+        },
+        Analysis: AnalysisConfig{
+            SecretPolicy:  "allow",
+            PrimaryIDHash: "sha256",
+        },
+        Batch: BatchConfig{
+            Workers:  4,
+            MaxItems: 1000,
+        },
+    }
+}
+
+// Load builds the configuration from CONFIG_FILE and the environment and
+// validates it. Every problem found is reported, not just the first.
+func Load() (Config, error) {
+    values := map[string]string{}
+    if path := os.Getenv("CONFIG_FILE"); path != "" {
+        fileValues, err := readFile(path)
+        if err != nil {
+            return Config{}, err
+        }
+        values = fileValues
+    }
+    return FromValues(values, os.LookupEnv)
+}
+
+// FromValues builds the configuration from file values, with lookupEnv
+// taking precedence. It is split out from Load for tests.
+func FromValues(fileValues map[string]string, lookupEnv func(string) (string, bool)) (Config, error) {
+    p := parser{fileValues: fileValues, lookupEnv: lookupEnv}
+    cfg := Default()
+
+    p.str("PORT", &cfg.Port)
+    p.str("STORAGE_BACKEND", &cfg.StorageBackend)
+
+    p.str("DB_PATH", &cfg.Database.Path)
+    p.str("DB_DSN", &cfg.Database.DSN)
+    p.str("DB_JOURNAL_MODE", &cfg.Database.JournalMode)
+    p.duration("DB_BUSY_TIMEOUT", &cfg.Database.BusyTimeout)
+    p.boolean("DB_FOREIGN_KEYS", &cfg.Database.ForeignKeys)
+    p.integer("DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+    p.integer("DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+    p.duration("DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+
+    p.duration("SERVER_READ_TIMEOUT", &cfg.Server.ReadTimeout)
+    p.duration("SERVER_READ_HEADER_TIMEOUT", &cfg.Server.ReadHeaderTimeout)
+    p.duration("SERVER_WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+    p.duration("SERVER_IDLE_TIMEOUT", &cfg.Server.IdleTimeout)
+    p.duration("SERVER_SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+    p.duration("IDEMPOTENCY_TTL", &cfg.Server.IdempotencyTTL)
+    p.integer("MAX_BODY_BYTES", &cfg.Server.MaxBodyBytes)
+
+    p.list("ANALYZERS_DISABLED", &cfg.Analysis.DisabledAnalyzers)
+    p.str("SECRET_POLICY", &cfg.Analysis.SecretPolicy)
+    p.str("PRIMARY_ID_HASH", &cfg.Analysis.PrimaryIDHash)
+
+    p.integer("BATCH_WORKERS", &cfg.Batch.Workers)
+    p.integer("BATCH_MAX_ITEMS", &cfg.Batch.MaxItems)
+
+    p.errs = append(p.errs, cfg.validate()...)
+    if len(p.errs) > 0 {
+        return cfg, fmt.Errorf("invalid configuration: %w", errors.Join(p.errs...))
+    }
+    return cfg, nil
+}
+
+var journalModes = map[string]bool{
+    "DELETE": true, "TRUNCATE": true, "PERSIST": true, "MEMORY": true, "WAL": true, "OFF": true,
+}
+
+func (cfg *Config) validate() []error {
+    var errs []error
+
+    if port, err := strconv.Atoi(cfg.Port); err != nil || port < 1 || port > 65535 {
+        errs = append(errs, fmt.Errorf("PORT must be a number between 1 and 65535, got %q", cfg.Port))
+    }
+    if cfg.StorageBackend != "sqlite" && cfg.StorageBackend != "memory" {
+        errs = append(errs, fmt.Errorf("STORAGE_BACKEND must be sqlite or memory, got %q", cfg.StorageBackend))
+    }
+
+    db := &cfg.Database
+    db.JournalMode = strings.ToUpper(db.JournalMode)
+    if db.DSN == "" && db.Path == "" {
+        errs = append(errs, errors.New("one of DB_PATH or DB_DSN must be set"))
+    }
+    if !journalModes[db.JournalMode] {
+        errs = append(errs, fmt.Errorf("DB_JOURNAL_MODE must be one of DELETE, TRUNCATE, PERSIST, MEMORY, WAL, OFF, got %q", db.JournalMode))
+    }
+    if db.BusyTimeout < 0 {
+        errs = append(errs, errors.New("DB_BUSY_TIMEOUT must not be negative"))
+    }
+    if db.MaxOpenConns < 0 {
+        errs = append(errs, errors.New("DB_MAX_OPEN_CONNS must not be negative"))
+    }
+    if db.MaxIdleConns < 0 {
+        errs = append(errs, errors.New("DB_MAX_IDLE_CONNS must not be negative"))
+    }
+    if db.MaxOpenConns > 0 && db.MaxIdleConns > db.MaxOpenConns {
+        errs = append(errs, fmt.Errorf("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", db.MaxIdleConns, db.MaxOpenConns))
+    }
+    if db.ConnMaxLifetime < 0 {
+        errs = append(errs, errors.New("DB_CONN_MAX_LIFETIME must not be negative"))
+    }
+
+    timeouts := map[string]time.Duration{
+        "SERVER_READ_TIMEOUT":        cfg.Server.ReadTimeout,
+        "SERVER_READ_HEADER_TIMEOUT": cfg.Server.ReadHeaderTimeout,
+        "SERVER_WRITE_TIMEOUT":       cfg.Server.WriteTimeout,
+        "SERVER_IDLE_TIMEOUT":        cfg.Server.IdleTimeout,
+        "SERVER_SHUTDOWN_TIMEOUT":    cfg.Server.ShutdownTimeout,
+    }
+    for name, timeout := range timeouts {
+        if timeout < 0 {
+            errs = append(errs, fmt.Errorf("%s must not be negative", name))
+        }
+    }
+    if cfg.Server.IdempotencyTTL <= 0 {
+        errs = append(errs, errors.New("IDEMPOTENCY_TTL must be positive"))
+    }
+    if cfg.Server.MaxBodyBytes < 1 {
+        errs = append(errs, errors.New("MAX_BODY_BYTES must be at least 1"))
+    }
+
+    switch cfg.Analysis.SecretPolicy {
+    case "allow", "redact", "reject":
+    default:
+        errs = append(errs, fmt.Errorf("SECRET_POLICY must be allow, redact or reject, got %q", cfg.Analysis.SecretPolicy))
+    }
+    // Checksums such as crc32 collide too easily to identify content
+    switch cfg.Analysis.PrimaryIDHash {
+    case "md5", "sha1", "sha256", "sha512", "blake2b":
+    default:
+        errs = append(errs, fmt.Errorf("PRIMARY_ID_HASH must be one of md5, sha1, sha256, sha512, blake2b, got %q", cfg.Analysis.PrimaryIDHash))
+    }
+
+    if cfg.Batch.Workers < 1 {
+        errs = append(errs, errors.New("BATCH_WORKERS must be at least 1"))
+    }
+    if cfg.Batch.MaxItems < 1 {
+        errs = append(errs, errors.New("BATCH_MAX_ITEMS must be at least 1"))
+    }
+
+    return errs
+}
+
+// DataSourceName returns the driver DSN, built from the individual settings
+// unless DSN was given explicitly.
+func (db DatabaseConfig) DataSourceName() string {
+    if db.DSN != "" {
+        return db.DSN
+    }
+    params := url.Values{}
+    params.Set("_journal_mode", db.JournalMode)
+    params.Set("_busy_timeout", strconv.FormatInt(db.BusyTimeout.Milliseconds(), 10))
+    params.Set("_foreign_keys", strconv.FormatBool(db.ForeignKeys))
+    return "file:" + db.Path + "?" + params.Encode()
+}
+
+// parser reads typed values, collecting errors instead of stopping at the first
+type parser struct {
+    fileValues map[string]string
+    lookupEnv  func(string) (string, bool)
+    errs       []error
+}
+
+func (p *parser) lookup(key string) (string, bool) {
+    if value, ok := p.lookupEnv(key); ok && value != "" {
+        return value, true
+    }
+    value, ok := p.fileValues[key]
+    return value, ok && value != ""
+}
+
+func (p *parser) str(key string, target *string) {
+    if value, ok := p.lookup(key); ok {
+        *target = value
+    }
+}
+
+func (p *parser) integer(key string, target *int) {
+    value, ok := p.lookup(key)
+    if !ok {
+        return
+    }
+    n, err := strconv.Atoi(value)
+    if err != nil {
+        p.errs = append(p.errs, fmt.Errorf("%s must be an integer, got %q", key, value))
+        return
+    }
+    *target = n
+}
+
+func (p *parser) boolean(key string, target *bool) {
+    value, ok := p.lookup(key)
+    if !ok {
+        return
+    }
+    b, err := strconv.ParseBool(value)
+    if err != nil {
+        p.errs = append(p.errs, fmt.Errorf("%s must be true or false, got %q", key, value))
+        return
+    }
+    *target = b
+}
+
+func (p *parser) duration(key string, target *time.Duration) {
+    value, ok := p.lookup(key)
+    if !ok {
+        return
+    }
+    d, err := time.ParseDuration(value)
+    if err != nil {
+        p.errs = append(p.errs, fmt.Errorf("%s must be a duration such as 5s or 1m, got %q", key, value))
+        return
+    }
+    *target = d
+}
+
+// list reads a comma-separated value, dropping blanks
+func (p *parser) list(key string, target *[]string) {
+    value, ok := p.lookup(key)
+    if !ok {
+        return
+    }
+    var items []string
+    for _, item := range strings.Split(value, ",") {
+        if item = strings.TrimSpace(item); item != "" {
+            items = append(items, item)
+        }
+    }
+    *target = items
+}
+
+// readFile parses a KEY=VALUE file. Blank lines and lines starting with #
+// are ignored, an "export " prefix is allowed and values may be quoted.
+func readFile(path string) (map[string]string, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, fmt.Errorf("reading config file: %w", err)
+    }
+    defer file.Close()
+
+    values := make(map[string]string)
+    scanner := bufio.NewScanner(file)
+    for lineNo := 1; scanner.Scan(); lineNo++ {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        line = strings.TrimPrefix(line, "export ")
+        key, value, ok := strings.Cut(line, "=")
+        if !ok {
+            return nil, fmt.Errorf("config file %s line %d: expected KEY=VALUE", path, lineNo)
+        }
+        value = strings.TrimSpace(value)
+        if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+            value = value[1 : len(value)-1]
+        }
+        values[strings.TrimSpace(key)] = value
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, fmt.Errorf("reading config file: %w", err)
+    }
+    return values, nil
+}
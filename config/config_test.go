@@ -0,0 +1,134 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+
+func envFrom(values map[string]string) func(string) (string, bool) {
+    return func(key string) (string, bool) {
+        value, ok := values[key]
+        return value, ok
+    }
+}
+
+// TestDefaults tests that an empty environment yields a valid configuration
+func TestDefaults(t *testing.T) {
+    cfg, err := FromValues(nil, envFrom(nil))
+    if err != nil {
+        t.Fatalf("Expected defaults to be valid, got %v", err)
+    }
+    if cfg.Port != "8080" || cfg.Database.Path != "./string_analyzer.db" || cfg.Database.JournalMode != "WAL" {
+        t.Errorf("Unexpected defaults: %+v", cfg)
+    }
+}
+
+// TestEnvironmentOverridesFile tests precedence and typed parsing
+func TestEnvironmentOverridesFile(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "app.env")
+    contents := "# comment\nPORT=9000\nexport DB_PATH=\"/data/file.db\"\nDB_BUSY_TIMEOUT=2s\n"
+    if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    fileValues, err := readFile(path)
+    if err != nil {
+        t.Fatalf("readFile failed: %v", err)
+    }
+    
+    cfg, err := FromValues(fileValues, envFrom(map[string]string{"PORT": "9100", "DB_JOURNAL_MODE": "delete"}))
+    if err != nil {
+        t.Fatalf("FromValues failed: %v", err)
+    }
+    if cfg.Port != "9100" {
+        t.Errorf("Expected env PORT to win, got %s", cfg.Port)
+    }
+    if cfg.Database.Path != "/data/file.db" || cfg.Database.BusyTimeout != 2*time.Second {
+        t.Errorf("File values not applied: %+v", cfg.Database)
+    }
+    if cfg.Database.JournalMode != "DELETE" {
+        t.Errorf("Expected journal mode to be normalized, got %s", cfg.Database.JournalMode)
+    }
+    
+    dsn := cfg.Database.DataSourceName()
+    if !strings.HasPrefix(dsn, "file:/data/file.db?") || !strings.Contains(dsn, "_busy_timeout=2000") {
+        t.Errorf("Unexpected DSN %s", dsn)
+    }
+}
+
+// TestValidationReportsEveryError tests that all invalid settings are listed
+func TestValidationReportsEveryError(t *testing.T) {
+    _, err := FromValues(nil, envFrom(map[string]string{
+        "PORT":              "http",
+        "DB_JOURNAL_MODE":   "fast",
+        "DB_MAX_OPEN_CONNS": "2",
+        "DB_MAX_IDLE_CONNS": "4",
+        "DB_BUSY_TIMEOUT":   "soon",
+    }))
+    if err == nil {
+        t.Fatal("Expected a validation error")
+    }
+    for _, key := range []string{"PORT", "DB_JOURNAL_MODE", "DB_MAX_IDLE_CONNS", "DB_BUSY_TIMEOUT"} {
+        if !strings.Contains(err.Error(), key) {
+            t.Errorf("Expected error to mention %s, got %v", key, err)
+        }
+    }
+}
+
+// TestSecretPolicy tests the default and validation of SECRET_POLICY
+func TestSecretPolicy(t *testing.T) {
+    cfg, err := FromValues(nil, envFrom(map[string]string{"SECRET_POLICY": "redact"}))
+    if err != nil || cfg.Analysis.SecretPolicy != "redact" {
+        t.Errorf("Expected redact, got %q, %v", cfg.Analysis.SecretPolicy, err)
+    }
+    if _, err := FromValues(nil, envFrom(map[string]string{"SECRET_POLICY": "ignore"})); err == nil || !strings.Contains(err.Error(), "SECRET_POLICY") {
+        t.Errorf("Expected a SECRET_POLICY error, got %v", err)
+    }
+}
+
+// TestPrimaryIDHash tests that checksums are refused as the ID digest
+func TestPrimaryIDHash(t *testing.T) {
+    cfg, err := FromValues(nil, envFrom(nil))
+    if err != nil || cfg.Analysis.PrimaryIDHash != "sha256" {
+        t.Errorf("Expected sha256 by default, got %q, %v", cfg.Analysis.PrimaryIDHash, err)
+    }
+    if _, err := FromValues(nil, envFrom(map[string]string{"PRIMARY_ID_HASH": "crc32"})); err == nil {
+        t.Error("Expected crc32 to be refused as PRIMARY_ID_HASH")
+    }
+}
+
+// TestBatchLimits tests the batch settings and their lower bounds
+func TestBatchLimits(t *testing.T) {
+    cfg, err := FromValues(nil, envFrom(map[string]string{"BATCH_WORKERS": "8"}))
+    if err != nil || cfg.Batch.Workers != 8 || cfg.Batch.MaxItems != 1000 {
+        t.Errorf("Unexpected batch settings %+v, %v", cfg.Batch, err)
+    }
+    _, err = FromValues(nil, envFrom(map[string]string{"BATCH_WORKERS": "-1", "BATCH_MAX_ITEMS": "-5"}))
+    if err == nil || !strings.Contains(err.Error(), "BATCH_WORKERS") || !strings.Contains(err.Error(), "BATCH_MAX_ITEMS") {
+        t.Errorf("Expected both batch settings to be refused, got %v", err)
+    }
+}
+
+// TestMaxBodyBytes tests the default and that the cap must be positive
+func TestMaxBodyBytes(t *testing.T) {
+    cfg, err := FromValues(nil, envFrom(nil))
+    if err != nil || cfg.Server.MaxBodyBytes != 10<<20 {
+        t.Errorf("Expected a 10 MiB default, got %d, %v", cfg.Server.MaxBodyBytes, err)
+    }
+    if _, err := FromValues(nil, envFrom(map[string]string{"MAX_BODY_BYTES": "0"})); err == nil {
+        t.Error("Expected a zero MAX_BODY_BYTES to be refused")
+    }
+}
+
+// TestIdempotencyTTL tests the default and that the TTL must be positive
+func TestIdempotencyTTL(t *testing.T) {
+    cfg, err := FromValues(nil, envFrom(nil))
+    if err != nil || cfg.Server.IdempotencyTTL != 24*time.Hour {
+        t.Errorf("Expected a 24h default, got %s, %v", cfg.Server.IdempotencyTTL, err)
+    }
+    if _, err := FromValues(nil, envFrom(map[string]string{"IDEMPOTENCY_TTL": "0s"})); err == nil {
+        t.Error("Expected a zero IDEMPOTENCY_TTL to be refused")
+    }
+}
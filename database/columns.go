@@ -0,0 +1,147 @@
+package database
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// column binds an analyzed_strings column to a field of models.AnalysisResult.
+// field returns a pointer to the field, which works both as a Scan
+// destination and as an Exec argument, so reads and writes share one list.
+type column struct {
+    name  string
+    field func(r *models.AnalysisResult) interface{}
+}
+
+// columns lists every analyzed_strings column in SELECT and INSERT order.
+// Adding a property means adding a migration and one entry here.
+var columns = []column{
+    {"id", func(r *models.AnalysisResult) interface{} { return &r.ID }},
+    {"value", func(r *models.AnalysisResult) interface{} { return &r.Value }},
+    {"length", func(r *models.AnalysisResult) interface{} { return &r.Length }},
+    {"byte_length", func(r *models.AnalysisResult) interface{} { return &r.ByteLength }},
+    {"rune_count", func(r *models.AnalysisResult) interface{} { return &r.RuneCount }},
+    {"grapheme_count", func(r *models.AnalysisResult) interface{} { return &r.GraphemeCount }},
+    {"is_palindrome", func(r *models.AnalysisResult) interface{} { return &r.IsPalindrome }},
+    {"palindrome_mode", func(r *models.AnalysisResult) interface{} { return &r.PalindromeMode }},
+    {"longest_palindrome", func(r *models.AnalysisResult) interface{} { return &r.LongestPalindrome }},
+    {"longest_palindrome_start", func(r *models.AnalysisResult) interface{} { return &r.LongestPalindromeStart }},
+    {"longest_palindrome_end", func(r *models.AnalysisResult) interface{} { return &r.LongestPalindromeEnd }},
+    {"longest_palindrome_length", func(r *models.AnalysisResult) interface{} { return &r.LongestPalindromeLength }},
+    {"distinct_palindromes", func(r *models.AnalysisResult) interface{} { return &r.DistinctPalindromes }},
+    {"palindromic_words", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.PalindromicWords} }},
+    {"unique_characters", func(r *models.AnalysisResult) interface{} { return &r.UniqueCharacters }},
+    {"word_count", func(r *models.AnalysisResult) interface{} { return &r.WordCount }},
+    {"sha256_hash", func(r *models.AnalysisResult) interface{} { return &r.SHA256Hash }},
+    {"character_frequency_map", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.CharacterFrequencyMap} }},
+    {"word_frequency_map", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.WordFrequencyMap} }},
+    {"word_bigrams", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.WordBigrams} }},
+    {"character_bigrams", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.CharacterBigrams} }},
+    {"character_trigrams", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.CharacterTrigrams} }},
+    {"top_words", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.TopWords} }},
+    {"most_common_word", func(r *models.AnalysisResult) interface{} { return &r.MostCommonWord }},
+    {"hapax_legomena", func(r *models.AnalysisResult) interface{} { return &r.HapaxLegomena }},
+    {"sentence_count", func(r *models.AnalysisResult) interface{} { return &r.SentenceCount }},
+    {"syllable_count", func(r *models.AnalysisResult) interface{} { return &r.SyllableCount }},
+    {"average_word_length", func(r *models.AnalysisResult) interface{} { return &r.AverageWordLength }},
+    {"flesch_reading_ease", func(r *models.AnalysisResult) interface{} { return &r.FleschReadingEase }},
+    {"flesch_kincaid_grade", func(r *models.AnalysisResult) interface{} { return &r.FleschKincaidGrade }},
+    {"lexical_diversity", func(r *models.AnalysisResult) interface{} { return &r.LexicalDiversity }},
+    {"shannon_entropy", func(r *models.AnalysisResult) interface{} { return &r.ShannonEntropy }},
+    {"normalized_entropy", func(r *models.AnalysisResult) interface{} { return &r.NormalizedEntropy }},
+    {"compression_ratio", func(r *models.AnalysisResult) interface{} { return &r.CompressionRatio }},
+    {"contains_secret", func(r *models.AnalysisResult) interface{} { return &r.ContainsSecret }},
+    {"secret_findings", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.SecretFindings} }},
+    {"pii_findings", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.PIIFindings} }},
+    {"pii_types", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.PIITypes} }},
+    {"redacted_value", func(r *models.AnalysisResult) interface{} { return &r.RedactedValue }},
+    {"category_counts", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.CategoryCounts} }},
+    {"script_counts", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.ScriptCounts} }},
+    {"emoji_count", func(r *models.AnalysisResult) interface{} { return &r.EmojiCount }},
+    {"mixed_script", func(r *models.AnalysisResult) interface{} { return &r.MixedScript }},
+    {"language", func(r *models.AnalysisResult) interface{} { return &r.Language }},
+    {"language_confidence", func(r *models.AnalysisResult) interface{} { return &r.LanguageConfidence }},
+    {"analyzer_versions", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.AnalyzerVersions} }},
+    {"custom_properties", func(r *models.AnalysisResult) interface{} { return jsonColumn{&r.CustomProperties} }},
+    {"created_at", func(r *models.AnalysisResult) interface{} { return &r.CreatedAt }},
+}
+
+// selectColumns is the column list every read query scans through scanResult
+var selectColumns = columnNames()
+
+func columnNames() string {
+    names := make([]string, len(columns))
+    for i, c := range columns {
+        names[i] = c.name
+    }
+    return strings.Join(names, ", ")
+}
+
+// insertQuery builds an INSERT covering every column
+func insertQuery() string {
+    placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+    return "INSERT INTO analyzed_strings (" + selectColumns + ") VALUES (" + placeholders + ")"
+}
+
+// keptOnUpdate are the columns a re-analysis never changes
+var keptOnUpdate = map[string]bool{"id": true, "value": true, "created_at": true}
+
+// updateQuery builds an UPDATE of every analysis column, keyed by value.
+// Its arguments are updateArgs followed by the value.
+func updateQuery() string {
+    var assignments []string
+    for _, c := range columns {
+        if !keptOnUpdate[c.name] {
+            assignments = append(assignments, c.name+" = ?")
+        }
+    }
+    return "UPDATE analyzed_strings SET " + strings.Join(assignments, ", ") + " WHERE value = ?"
+}
+
+// updateArgs returns the field pointers matching updateQuery's SET list
+func updateArgs(result *models.AnalysisResult) []interface{} {
+    var fields []interface{}
+    for _, c := range columns {
+        if !keptOnUpdate[c.name] {
+            fields = append(fields, c.field(result))
+        }
+    }
+    return fields
+}
+
+// fieldPointers returns one pointer per column into result
+func fieldPointers(result *models.AnalysisResult) []interface{} {
+    fields := make([]interface{}, len(columns))
+    for i, c := range columns {
+        fields[i] = c.field(result)
+    }
+    return fields
+}
+
+// jsonColumn stores a Go value as JSON text
+type jsonColumn struct {
+    target interface{}
+}
+
+func (j jsonColumn) Value() (driver.Value, error) {
+    data, err := json.Marshal(j.target)
+    if err != nil {
+        return nil, err
+    }
+    return string(data), nil
+}
+
+func (j jsonColumn) Scan(src interface{}) error {
+    switch data := src.(type) {
+    case string:
+        return json.Unmarshal([]byte(data), j.target)
+    case []byte:
+        return json.Unmarshal(data, j.target)
+    case nil:
+        return nil
+    }
+    return fmt.Errorf("cannot scan %T into a JSON column", src)
+}
@@ -0,0 +1,263 @@
+package database
+
+import (
+    "database/sql"
+    "embed"
+    "errors"
+    "fmt"
+    "log"
+    "path"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Migrations live in migrations/ as NNNN_name.up.sql and NNNN_name.down.sql.
+// Versions must be unique and are applied in ascending order; never edit a
+// migration that has shipped, add a new one instead.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrSchemaTooNew means the database was migrated by a newer build than this one
+var ErrSchemaTooNew = errors.New("database schema is newer than this build supports")
+
+// Migration is one versioned schema change
+type Migration struct {
+    Version int
+    Name    string
+    Up      string
+    Down    string
+}
+
+// MigrationStatus pairs a known migration with whether it has been applied
+type MigrationStatus struct {
+    Migration
+    Applied bool
+}
+
+// loadMigrations reads the embedded migration files, sorted by version
+func loadMigrations() ([]Migration, error) {
+    entries, err := migrationFiles.ReadDir("migrations")
+    if err != nil {
+        return nil, err
+    }
+
+    byVersion := make(map[int]*Migration)
+    for _, entry := range entries {
+        fileName := entry.Name()
+        var direction string
+        switch {
+        case strings.HasSuffix(fileName, ".up.sql"):
+            direction = "up"
+        case strings.HasSuffix(fileName, ".down.sql"):
+            direction = "down"
+        default:
+            return nil, fmt.Errorf("unexpected migration file %s", fileName)
+        }
+
+        base := strings.TrimSuffix(fileName, "."+direction+".sql")
+        versionStr, name, ok := strings.Cut(base, "_")
+        if !ok {
+            return nil, fmt.Errorf("migration file %s is not named NNNN_name.%s.sql", fileName, direction)
+        }
+        version, err := strconv.Atoi(versionStr)
+        if err != nil || version <= 0 {
+            return nil, fmt.Errorf("migration file %s has an invalid version", fileName)
+        }
+
+        contents, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+        if err != nil {
+            return nil, err
+        }
+
+        migration, exists := byVersion[version]
+        if !exists {
+            migration = &Migration{Version: version, Name: name}
+            byVersion[version] = migration
+        } else if migration.Name != name {
+            return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, name)
+        }
+        if direction == "up" {
+            migration.Up = string(contents)
+        } else {
+            migration.Down = string(contents)
+        }
+    }
+
+    migrations := make([]Migration, 0, len(byVersion))
+    for _, migration := range byVersion {
+        if migration.Up == "" {
+            return nil, fmt.Errorf("migration %04d_%s has no up script", migration.Version, migration.Name)
+        }
+        migrations = append(migrations, *migration)
+    }
+    sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+    return migrations, nil
+}
+
+// LatestVersion is the highest migration version embedded in this build
+func LatestVersion() (int, error) {
+    migrations, err := loadMigrations()
+    if err != nil {
+        return 0, err
+    }
+    if len(migrations) == 0 {
+        return 0, nil
+    }
+    return migrations[len(migrations)-1].Version, nil
+}
+
+func ensureMigrationsTable(db *sql.DB) error {
+    _, err := db.Exec(`
+    CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        applied_at TEXT NOT NULL
+    )
+    `)
+    return err
+}
+
+func appliedVersions(db *sql.DB) (map[int]bool, error) {
+    rows, err := db.Query("SELECT version FROM schema_migrations")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    applied := make(map[int]bool)
+    for rows.Next() {
+        var version int
+        if err := rows.Scan(&version); err != nil {
+            return nil, err
+        }
+        applied[version] = true
+    }
+    return applied, rows.Err()
+}
+
+// SchemaVersion returns the highest applied migration version, 0 for a fresh database
+func SchemaVersion(db *sql.DB) (int, error) {
+    if err := ensureMigrationsTable(db); err != nil {
+        return 0, err
+    }
+    var version int
+    err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+    return version, err
+}
+
+// CheckSchema refuses databases migrated past what this build knows about
+// and reports whether migrations are still pending.
+func CheckSchema(db *sql.DB) (pending bool, err error) {
+    current, err := SchemaVersion(db)
+    if err != nil {
+        return false, err
+    }
+    latest, err := LatestVersion()
+    if err != nil {
+        return false, err
+    }
+    if current > latest {
+        return false, fmt.Errorf("%w (database at version %d, build supports up to %d)", ErrSchemaTooNew, current, latest)
+    }
+    return current < latest, nil
+}
+
+// MigrationStatuses lists every embedded migration and whether it is applied
+func MigrationStatuses(db *sql.DB) ([]MigrationStatus, error) {
+    migrations, err := loadMigrations()
+    if err != nil {
+        return nil, err
+    }
+    if err := ensureMigrationsTable(db); err != nil {
+        return nil, err
+    }
+    applied, err := appliedVersions(db)
+    if err != nil {
+        return nil, err
+    }
+
+    statuses := make([]MigrationStatus, len(migrations))
+    for i, migration := range migrations {
+        statuses[i] = MigrationStatus{Migration: migration, Applied: applied[migration.Version]}
+    }
+    return statuses, nil
+}
+
+// Migrate applies every pending migration in order, each in its own transaction
+func Migrate(db *sql.DB) error {
+    if _, err := CheckSchema(db); err != nil {
+        return err
+    }
+    statuses, err := MigrationStatuses(db)
+    if err != nil {
+        return err
+    }
+
+    for _, status := range statuses {
+        if status.Applied {
+            continue
+        }
+        err := runInTx(db, func(tx *sql.Tx) error {
+            if _, err := tx.Exec(status.Up); err != nil {
+                return err
+            }
+            _, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+                status.Version, status.Name, time.Now().UTC().Format(time.RFC3339))
+            return err
+        })
+        if err != nil {
+            return fmt.Errorf("migration %04d_%s failed: %w", status.Version, status.Name, err)
+        }
+        log.Printf("Applied migration %04d_%s", status.Version, status.Name)
+    }
+    return nil
+}
+
+// MigrateDown reverts the most recently applied migrations, newest first
+func MigrateDown(db *sql.DB, steps int) error {
+    if _, err := CheckSchema(db); err != nil {
+        return err
+    }
+    statuses, err := MigrationStatuses(db)
+    if err != nil {
+        return err
+    }
+
+    for i := len(statuses) - 1; i >= 0 && steps > 0; i-- {
+        status := statuses[i]
+        if !status.Applied {
+            continue
+        }
+        if status.Down == "" {
+            return fmt.Errorf("migration %04d_%s cannot be reverted: no down script", status.Version, status.Name)
+        }
+        err := runInTx(db, func(tx *sql.Tx) error {
+            if _, err := tx.Exec(status.Down); err != nil {
+                return err
+            }
+            _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", status.Version)
+            return err
+        })
+        if err != nil {
+            return fmt.Errorf("reverting migration %04d_%s failed: %w", status.Version, status.Name, err)
+        }
+        log.Printf("Reverted migration %04d_%s", status.Version, status.Name)
+        steps--
+    }
+    return nil
+}
+
+func runInTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+    tx, err := db.Begin()
+    if err != nil {
+        return err
+    }
+    if err := fn(tx); err != nil {
+        tx.Rollback()
+        return err
+    }
+    return tx.Commit()
+}
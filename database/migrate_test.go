@@ -0,0 +1,64 @@
+package database
+
+import (
+    "database/sql"
+    "errors"
+    "path/filepath"
+    "testing"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+    t.Helper()
+    db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+    if err != nil {
+        t.Fatalf("Failed to open database: %v", err)
+    }
+    t.Cleanup(func() { db.Close() })
+    return db
+}
+
+// TestMigrateUpAndDown tests applying and reverting every embedded migration
+func TestMigrateUpAndDown(t *testing.T) {
+    db := openTestDB(t)
+    latest, err := LatestVersion()
+    if err != nil {
+        t.Fatalf("LatestVersion failed: %v", err)
+    }
+    
+    if err := Migrate(db); err != nil {
+        t.Fatalf("Migrate failed: %v", err)
+    }
+    if version, _ := SchemaVersion(db); version != latest {
+        t.Errorf("Expected schema version %d, got %d", latest, version)
+    }
+    // Running again is a no-op
+    if err := Migrate(db); err != nil {
+        t.Fatalf("Second Migrate failed: %v", err)
+    }
+    
+    if err := MigrateDown(db, latest); err != nil {
+        t.Fatalf("MigrateDown failed: %v", err)
+    }
+    if version, _ := SchemaVersion(db); version != 0 {
+        t.Errorf("Expected schema version 0 after reverting, got %d", version)
+    }
+    if err := Migrate(db); err != nil {
+        t.Fatalf("Migrate after revert failed: %v", err)
+    }
+}
+
+// TestMigrateRefusesNewerSchema tests the startup guard against newer databases
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+    db := openTestDB(t)
+    if err := Migrate(db); err != nil {
+        t.Fatalf("Migrate failed: %v", err)
+    }
+    latest, _ := LatestVersion()
+    if _, err := db.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'from_the_future', '')", latest+1); err != nil {
+        t.Fatalf("Failed to fake a newer schema: %v", err)
+    }
+    
+    if err := Migrate(db); !errors.Is(err, ErrSchemaTooNew) {
+        t.Errorf("Expected ErrSchemaTooNew, got %v", err)
+    }
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS analyzed_strings;
@@ -0,0 +1,12 @@
+-- IF NOT EXISTS keeps this safe for databases created before migrations existed
+CREATE TABLE IF NOT EXISTS analyzed_strings (
+    id TEXT PRIMARY KEY,
+    value TEXT UNIQUE NOT NULL,
+    length INTEGER NOT NULL,
+    is_palindrome BOOLEAN NOT NULL,
+    unique_characters INTEGER NOT NULL,
+    word_count INTEGER NOT NULL,
+    sha256_hash TEXT NOT NULL,
+    character_frequency_map TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
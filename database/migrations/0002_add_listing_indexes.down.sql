@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_is_palindrome;
+DROP INDEX IF EXISTS idx_analyzed_strings_length;
+DROP INDEX IF EXISTS idx_analyzed_strings_word_count;
+DROP INDEX IF EXISTS idx_analyzed_strings_unique_characters;
+DROP INDEX IF EXISTS idx_analyzed_strings_created_at;
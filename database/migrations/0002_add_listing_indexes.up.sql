@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_is_palindrome ON analyzed_strings (is_palindrome);
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_length ON analyzed_strings (length);
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_word_count ON analyzed_strings (word_count);
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_unique_characters ON analyzed_strings (unique_characters);
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_created_at ON analyzed_strings (created_at);
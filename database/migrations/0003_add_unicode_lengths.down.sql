@@ -0,0 +1,5 @@
+UPDATE analyzed_strings SET length = byte_length;
+
+ALTER TABLE analyzed_strings DROP COLUMN grapheme_count;
+ALTER TABLE analyzed_strings DROP COLUMN rune_count;
+ALTER TABLE analyzed_strings DROP COLUMN byte_length;
@@ -0,0 +1,12 @@
+ALTER TABLE analyzed_strings ADD COLUMN byte_length INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN rune_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN grapheme_count INTEGER NOT NULL DEFAULT 0;
+
+-- length used to hold the UTF-8 byte count; it now holds code points, which is
+-- what SQLite's length() returns for text. Grapheme clusters cannot be counted
+-- in SQL, so existing rows fall back to the code point count until re-analyzed.
+UPDATE analyzed_strings SET
+    byte_length = length(CAST(value AS BLOB)),
+    rune_count = length(value),
+    grapheme_count = length(value),
+    length = length(value);
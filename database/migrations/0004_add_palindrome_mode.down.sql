@@ -0,0 +1 @@
+ALTER TABLE analyzed_strings DROP COLUMN palindrome_mode;
@@ -0,0 +1,2 @@
+-- Rows stored before modes existed were checked with whitespace ignored
+ALTER TABLE analyzed_strings ADD COLUMN palindrome_mode TEXT NOT NULL DEFAULT 'ignore-whitespace';
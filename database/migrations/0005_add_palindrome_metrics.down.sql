@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_distinct_palindromes;
+DROP INDEX IF EXISTS idx_analyzed_strings_longest_palindrome_length;
+
+ALTER TABLE analyzed_strings DROP COLUMN palindromic_words;
+ALTER TABLE analyzed_strings DROP COLUMN distinct_palindromes;
+ALTER TABLE analyzed_strings DROP COLUMN longest_palindrome_length;
+ALTER TABLE analyzed_strings DROP COLUMN longest_palindrome_end;
+ALTER TABLE analyzed_strings DROP COLUMN longest_palindrome_start;
+ALTER TABLE analyzed_strings DROP COLUMN longest_palindrome;
@@ -0,0 +1,10 @@
+-- Existing rows keep the zero values until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN longest_palindrome TEXT NOT NULL DEFAULT '';
+ALTER TABLE analyzed_strings ADD COLUMN longest_palindrome_start INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN longest_palindrome_end INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN longest_palindrome_length INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN distinct_palindromes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN palindromic_words TEXT NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_longest_palindrome_length ON analyzed_strings (longest_palindrome_length);
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_distinct_palindromes ON analyzed_strings (distinct_palindromes);
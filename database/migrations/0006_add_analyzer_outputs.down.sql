@@ -0,0 +1,2 @@
+ALTER TABLE analyzed_strings DROP COLUMN custom_properties;
+ALTER TABLE analyzed_strings DROP COLUMN analyzer_versions;
@@ -0,0 +1,3 @@
+-- An empty analyzer_versions map marks rows analyzed before the registry existed
+ALTER TABLE analyzed_strings ADD COLUMN analyzer_versions TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE analyzed_strings ADD COLUMN custom_properties TEXT NOT NULL DEFAULT '{}';
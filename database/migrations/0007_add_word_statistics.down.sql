@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_hapax_legomena;
+DROP INDEX IF EXISTS idx_analyzed_strings_most_common_word;
+
+ALTER TABLE analyzed_strings DROP COLUMN hapax_legomena;
+ALTER TABLE analyzed_strings DROP COLUMN most_common_word;
+ALTER TABLE analyzed_strings DROP COLUMN top_words;
+ALTER TABLE analyzed_strings DROP COLUMN character_trigrams;
+ALTER TABLE analyzed_strings DROP COLUMN character_bigrams;
+ALTER TABLE analyzed_strings DROP COLUMN word_bigrams;
+ALTER TABLE analyzed_strings DROP COLUMN word_frequency_map;
@@ -0,0 +1,11 @@
+-- Existing rows keep the empty defaults until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN word_frequency_map TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE analyzed_strings ADD COLUMN word_bigrams TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE analyzed_strings ADD COLUMN character_bigrams TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE analyzed_strings ADD COLUMN character_trigrams TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE analyzed_strings ADD COLUMN top_words TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE analyzed_strings ADD COLUMN most_common_word TEXT NOT NULL DEFAULT '';
+ALTER TABLE analyzed_strings ADD COLUMN hapax_legomena INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_most_common_word ON analyzed_strings (most_common_word);
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_hapax_legomena ON analyzed_strings (hapax_legomena);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_flesch_kincaid_grade;
+DROP INDEX IF EXISTS idx_analyzed_strings_flesch_reading_ease;
+
+ALTER TABLE analyzed_strings DROP COLUMN lexical_diversity;
+ALTER TABLE analyzed_strings DROP COLUMN flesch_kincaid_grade;
+ALTER TABLE analyzed_strings DROP COLUMN flesch_reading_ease;
+ALTER TABLE analyzed_strings DROP COLUMN average_word_length;
+ALTER TABLE analyzed_strings DROP COLUMN syllable_count;
+ALTER TABLE analyzed_strings DROP COLUMN sentence_count;
@@ -0,0 +1,10 @@
+-- Existing rows keep the zero values until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN sentence_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN syllable_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN average_word_length REAL NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN flesch_reading_ease REAL NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN flesch_kincaid_grade REAL NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN lexical_diversity REAL NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_flesch_reading_ease ON analyzed_strings (flesch_reading_ease);
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_flesch_kincaid_grade ON analyzed_strings (flesch_kincaid_grade);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_shannon_entropy;
+
+ALTER TABLE analyzed_strings DROP COLUMN compression_ratio;
+ALTER TABLE analyzed_strings DROP COLUMN normalized_entropy;
+ALTER TABLE analyzed_strings DROP COLUMN shannon_entropy;
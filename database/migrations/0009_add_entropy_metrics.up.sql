@@ -0,0 +1,6 @@
+-- Existing rows keep the zero values until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN shannon_entropy REAL NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN normalized_entropy REAL NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN compression_ratio REAL NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_shannon_entropy ON analyzed_strings (shannon_entropy);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_contains_secret;
+
+ALTER TABLE analyzed_strings DROP COLUMN secret_findings;
+ALTER TABLE analyzed_strings DROP COLUMN contains_secret;
@@ -0,0 +1,5 @@
+-- Existing rows are reported as clean until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN contains_secret BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN secret_findings TEXT NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_contains_secret ON analyzed_strings (contains_secret);
@@ -0,0 +1,3 @@
+ALTER TABLE analyzed_strings DROP COLUMN redacted_value;
+ALTER TABLE analyzed_strings DROP COLUMN pii_types;
+ALTER TABLE analyzed_strings DROP COLUMN pii_findings;
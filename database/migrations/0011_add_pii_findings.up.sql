@@ -0,0 +1,4 @@
+-- Existing rows are reported as clean until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN pii_findings TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE analyzed_strings ADD COLUMN pii_types TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE analyzed_strings ADD COLUMN redacted_value TEXT NOT NULL DEFAULT '';
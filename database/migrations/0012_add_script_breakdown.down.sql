@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_mixed_script;
+
+ALTER TABLE analyzed_strings DROP COLUMN mixed_script;
+ALTER TABLE analyzed_strings DROP COLUMN emoji_count;
+ALTER TABLE analyzed_strings DROP COLUMN script_counts;
+ALTER TABLE analyzed_strings DROP COLUMN category_counts;
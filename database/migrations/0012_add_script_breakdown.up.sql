@@ -0,0 +1,7 @@
+-- Existing rows keep the empty defaults until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN category_counts TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE analyzed_strings ADD COLUMN script_counts TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE analyzed_strings ADD COLUMN emoji_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE analyzed_strings ADD COLUMN mixed_script BOOLEAN NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_mixed_script ON analyzed_strings (mixed_script);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_analyzed_strings_language;
+
+ALTER TABLE analyzed_strings DROP COLUMN language_confidence;
+ALTER TABLE analyzed_strings DROP COLUMN language;
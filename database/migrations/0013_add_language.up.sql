@@ -0,0 +1,5 @@
+-- Existing rows stay undetermined until they are re-analyzed
+ALTER TABLE analyzed_strings ADD COLUMN language TEXT NOT NULL DEFAULT '';
+ALTER TABLE analyzed_strings ADD COLUMN language_confidence REAL NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_analyzed_strings_language ON analyzed_strings (language);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_string_hashes_digest;
+DROP TABLE IF EXISTS string_hashes;
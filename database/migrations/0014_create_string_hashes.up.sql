@@ -0,0 +1,13 @@
+-- Digests by algorithm, for lookups by hash. A digest may belong to several
+-- strings for checksums such as crc32.
+CREATE TABLE IF NOT EXISTS string_hashes (
+    string_id TEXT NOT NULL REFERENCES analyzed_strings (id) ON DELETE CASCADE,
+    algorithm TEXT NOT NULL,
+    digest TEXT NOT NULL,
+    PRIMARY KEY (string_id, algorithm)
+);
+
+CREATE INDEX IF NOT EXISTS idx_string_hashes_digest ON string_hashes (algorithm, digest);
+
+INSERT OR IGNORE INTO string_hashes (string_id, algorithm, digest)
+SELECT id, 'sha256', sha256_hash FROM analyzed_strings;
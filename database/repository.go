@@ -0,0 +1,573 @@
+package database
+
+import (
+    "database/sql"
+    "strings"
+    "time"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/storage"
+)
+
+// SQLiteRepository implements storage.StringRepository on top of a SQLite handle
+type SQLiteRepository struct {
+    db *sql.DB
+}
+
+// NewSQLiteRepository wraps an open database handle, normally DB after Init
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+    return &SQLiteRepository{db: db}
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+    Scan(dest ...interface{}) error
+}
+
+func scanResult(row scanner) (models.AnalysisResult, error) {
+    var result models.AnalysisResult
+    err := row.Scan(fieldPointers(&result)...)
+    return result, err
+}
+
+// StoreString inserts the row and its digests in one transaction
+func (r *SQLiteRepository) StoreString(result models.AnalysisResult) error {
+    return runInTx(r.db, func(tx *sql.Tx) error {
+        if _, err := tx.Exec(insertQuery(), fieldPointers(&result)...); err != nil {
+            return err
+        }
+        return storeHashes(tx, result)
+    })
+}
+
+// CreateOrGetString inserts result or, when its value is already stored,
+// returns the stored row. ON CONFLICT makes this atomic, so concurrent
+// requests for the same value cannot fail on the UNIQUE constraint.
+func (r *SQLiteRepository) CreateOrGetString(result models.AnalysisResult) (models.AnalysisResult, bool, error) {
+    var existing models.AnalysisResult
+    created := false
+    err := runInTx(r.db, func(tx *sql.Tx) error {
+        res, err := tx.Exec(insertQuery()+" ON CONFLICT DO NOTHING", fieldPointers(&result)...)
+        if err != nil {
+            return err
+        }
+        n, err := res.RowsAffected()
+        if err != nil {
+            return err
+        }
+        if n > 0 {
+            created = true
+            return storeHashes(tx, result)
+        }
+        existing, err = scanResult(tx.QueryRow("SELECT "+selectColumns+" FROM analyzed_strings WHERE value = ?", result.Value))
+        return err
+    })
+    if err != nil {
+        return result, false, err
+    }
+    if created {
+        return result, true, nil
+    }
+    
+    results := []models.AnalysisResult{existing}
+    if err := r.loadHashes(results); err != nil {
+        return existing, false, err
+    }
+    return results[0], false, nil
+}
+
+// UpsertString replaces the analysis of a stored value, keeping its ID and
+// created_at, or inserts it. The UPDATE runs first so the transaction takes
+// the write lock straight away and concurrent upserts queue up behind it.
+func (r *SQLiteRepository) UpsertString(result models.AnalysisResult) (models.AnalysisResult, bool, error) {
+    created := false
+    err := runInTx(r.db, func(tx *sql.Tx) error {
+        var err error
+        created, err = upsertTx(tx, &result)
+        return err
+    })
+    if err != nil {
+        return result, false, err
+    }
+    return result, created, nil
+}
+
+// UpsertStrings upserts a batch in one transaction. A value repeated within
+// the batch is created by its first occurrence and updated by the rest.
+func (r *SQLiteRepository) UpsertStrings(results []models.AnalysisResult) ([]bool, error) {
+    created := make([]bool, len(results))
+    err := runInTx(r.db, func(tx *sql.Tx) error {
+        for i := range results {
+            var err error
+            if created[i], err = upsertTx(tx, &results[i]); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return created, nil
+}
+
+// upsertTx updates or inserts result within tx, setting the stored ID and
+// created_at on an update, and reports whether it was inserted
+func upsertTx(tx *sql.Tx, result *models.AnalysisResult) (bool, error) {
+    res, err := tx.Exec(updateQuery(), append(updateArgs(result), result.Value)...)
+    if err != nil {
+        return false, err
+    }
+    n, err := res.RowsAffected()
+    if err != nil {
+        return false, err
+    }
+    if n == 0 {
+        if _, err := tx.Exec(insertQuery(), fieldPointers(result)...); err != nil {
+            return false, err
+        }
+        return true, storeHashes(tx, *result)
+    }
+    
+    err = tx.QueryRow("SELECT id, created_at FROM analyzed_strings WHERE value = ?", result.Value).Scan(&result.ID, &result.CreatedAt)
+    if err != nil {
+        return false, err
+    }
+    if _, err := tx.Exec("DELETE FROM string_hashes WHERE string_id = ?", result.ID); err != nil {
+        return false, err
+    }
+    return false, storeHashes(tx, *result)
+}
+
+// StoreStrings inserts a batch in one transaction. ON CONFLICT DO NOTHING
+// skips values that are already stored, including repeats within the batch,
+// without aborting the rest.
+func (r *SQLiteRepository) StoreStrings(results []models.AnalysisResult) ([]bool, error) {
+    inserted := make([]bool, len(results))
+    err := runInTx(r.db, func(tx *sql.Tx) error {
+        stmt, err := tx.Prepare(insertQuery() + " ON CONFLICT DO NOTHING")
+        if err != nil {
+            return err
+        }
+        defer stmt.Close()
+        
+        for i := range results {
+            res, err := stmt.Exec(fieldPointers(&results[i])...)
+            if err != nil {
+                return err
+            }
+            n, err := res.RowsAffected()
+            if err != nil {
+                return err
+            }
+            if n == 0 {
+                continue
+            }
+            inserted[i] = true
+            if err := storeHashes(tx, results[i]); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return inserted, nil
+}
+
+func storeHashes(tx *sql.Tx, result models.AnalysisResult) error {
+    for algorithm, digest := range result.Hashes {
+        _, err := tx.Exec("INSERT OR REPLACE INTO string_hashes (string_id, algorithm, digest) VALUES (?, ?, ?)",
+            result.ID, algorithm, digest)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// loadHashes fills in Hashes for results read from analyzed_strings
+func (r *SQLiteRepository) loadHashes(results []models.AnalysisResult) error {
+    if len(results) == 0 {
+        return nil
+    }
+    byID := make(map[string]*models.AnalysisResult, len(results))
+    placeholders := make([]string, len(results))
+    args := make([]interface{}, len(results))
+    for i := range results {
+        results[i].Hashes = map[string]string{"sha256": results[i].SHA256Hash}
+        byID[results[i].ID] = &results[i]
+        placeholders[i] = "?"
+        args[i] = results[i].ID
+    }
+    
+    rows, err := r.db.Query("SELECT string_id, algorithm, digest FROM string_hashes WHERE string_id IN ("+strings.Join(placeholders, ", ")+")", args...)
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+    for rows.Next() {
+        var id, algorithm, digest string
+        if err := rows.Scan(&id, &algorithm, &digest); err != nil {
+            return err
+        }
+        if result, ok := byID[id]; ok {
+            result.Hashes[algorithm] = digest
+        }
+    }
+    return rows.Err()
+}
+
+func (r *SQLiteRepository) GetString(value string) (models.AnalysisResult, bool, error) {
+    return r.getBy("value", value)
+}
+
+// GetStringByID looks a string up by its primary key
+func (r *SQLiteRepository) GetStringByID(id string) (models.AnalysisResult, bool, error) {
+    return r.getBy("id", id)
+}
+
+// getBy reads the single row whose column equals key; column is never user input
+func (r *SQLiteRepository) getBy(column, key string) (models.AnalysisResult, bool, error) {
+    query := "SELECT " + selectColumns + " FROM analyzed_strings WHERE " + column + " = ?"
+    result, err := scanResult(r.db.QueryRow(query, key))
+    
+    if err == sql.ErrNoRows {
+        return result, false, nil
+    }
+    if err != nil {
+        return result, false, err
+    }
+    
+    results := []models.AnalysisResult{result}
+    if err := r.loadHashes(results); err != nil {
+        return result, false, err
+    }
+    return results[0], true, nil
+}
+
+// EachString streams matching rows from a single cursor. The digests are
+// aggregated into the same query, since a second query per row could wait
+// forever for a connection when the pool is limited to one.
+func (r *SQLiteRepository) EachString(filters storage.Filters, fn func(models.AnalysisResult) error) error {
+    where, args := buildWhere(filters)
+    query := "SELECT " + selectColumns + ", (SELECT json_group_object(algorithm, digest) FROM string_hashes WHERE string_id = analyzed_strings.id) FROM analyzed_strings"
+    if len(where) > 0 {
+        query += " WHERE " + strings.Join(where, " AND ")
+    }
+    query += " ORDER BY created_at, id"
+    
+    rows, err := r.db.Query(query, args...)
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+    
+    for rows.Next() {
+        var result models.AnalysisResult
+        dest := append(fieldPointers(&result), jsonColumn{&result.Hashes})
+        if err := rows.Scan(dest...); err != nil {
+            return err
+        }
+        if result.Hashes == nil {
+            result.Hashes = map[string]string{}
+        }
+        if _, ok := result.Hashes["sha256"]; !ok {
+            result.Hashes["sha256"] = result.SHA256Hash
+        }
+        if err := fn(result); err != nil {
+            return err
+        }
+    }
+    return rows.Err()
+}
+
+// FindByHash returns the strings whose digest under algorithm matches
+func (r *SQLiteRepository) FindByHash(algorithm, digest string) ([]models.AnalysisResult, error) {
+    query := "SELECT " + selectColumns + " FROM analyzed_strings WHERE id IN " +
+        "(SELECT string_id FROM string_hashes WHERE algorithm = ? AND digest = ?) ORDER BY id"
+    rows, err := r.db.Query(query, algorithm, digest)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+    
+    results := make([]models.AnalysisResult, 0)
+    for rows.Next() {
+        result, err := scanResult(rows)
+        if err != nil {
+            return nil, err
+        }
+        results = append(results, result)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    return results, r.loadHashes(results)
+}
+
+func (r *SQLiteRepository) ListStrings(q storage.Query) (storage.Page, error) {
+    where, args := buildWhere(q.Filters)
+    
+    cursor, hasCursor, err := q.StartAfter()
+    if err != nil {
+        return storage.Page{}, err
+    }
+    if hasCursor {
+        condition, cursorArgs := keysetCondition(q.Sort, cursor)
+        where = append(where, condition)
+        args = append(args, cursorArgs...)
+    }
+    
+    query := "SELECT " + selectColumns + " FROM analyzed_strings"
+    if len(where) > 0 {
+        query += " WHERE " + strings.Join(where, " AND ")
+    }
+    // Fetch one extra row to find out whether another page exists
+    limit := q.PageSize()
+    query += " ORDER BY " + orderBy(q.Sort) + " LIMIT ?"
+    args = append(args, limit+1)
+    
+    rows, err := r.db.Query(query, args...)
+    if err != nil {
+        return storage.Page{}, err
+    }
+    defer rows.Close()
+    
+    results := make([]models.AnalysisResult, 0)
+    for rows.Next() {
+        result, err := scanResult(rows)
+        if err != nil {
+            return storage.Page{}, err
+        }
+        results = append(results, result)
+    }
+    if err := rows.Err(); err != nil {
+        return storage.Page{}, err
+    }
+    if err := r.loadHashes(results); err != nil {
+        return storage.Page{}, err
+    }
+    
+    page := storage.Page{Results: results}
+    if len(results) > limit {
+        page.Results = results[:limit]
+        page.NextCursor = storage.EncodeCursor(q.Sort.CursorFor(results[limit-1]))
+    }
+    return page, nil
+}
+
+// orderBy renders the sort spec with id as the final tiebreaker
+func orderBy(sort storage.Sort) string {
+    terms := make([]string, 0, len(sort)+1)
+    for _, field := range sort {
+        if field.Desc {
+            terms = append(terms, field.Column()+" DESC")
+        } else {
+            terms = append(terms, field.Column()+" ASC")
+        }
+    }
+    return strings.Join(append(terms, "id ASC"), ", ")
+}
+
+// keysetCondition selects the rows that sort after the cursor. For keys
+// (a, b, id) it expands to a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?),
+// flipping the comparison for descending keys.
+func keysetCondition(sort storage.Sort, cursor storage.Cursor) (string, []interface{}) {
+    columns := make([]string, 0, len(sort)+1)
+    operators := make([]string, 0, len(sort)+1)
+    for _, field := range sort {
+        columns = append(columns, field.Column())
+        if field.Desc {
+            operators = append(operators, "<")
+        } else {
+            operators = append(operators, ">")
+        }
+    }
+    columns = append(columns, "id")
+    operators = append(operators, ">")
+    values := append(append([]interface{}{}, cursor.Values...), cursor.ID)
+    
+    var terms []string
+    var args []interface{}
+    for i := range columns {
+        var parts []string
+        for j := 0; j < i; j++ {
+            parts = append(parts, columns[j]+" = ?")
+            args = append(args, values[j])
+        }
+        parts = append(parts, columns[i]+" "+operators[i]+" ?")
+        args = append(args, values[i])
+        terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+    }
+    return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// buildWhere translates filters into parameterized WHERE conditions
+func buildWhere(f storage.Filters) ([]string, []interface{}) {
+    var where []string
+    var args []interface{}
+    
+    if f.IsPalindrome != nil {
+        where = append(where, "is_palindrome = ?")
+        args = append(args, *f.IsPalindrome)
+    }
+    if f.MinLength != nil {
+        where = append(where, "length >= ?")
+        args = append(args, *f.MinLength)
+    }
+    if f.MaxLength != nil {
+        where = append(where, "length <= ?")
+        args = append(args, *f.MaxLength)
+    }
+    if f.WordCount != nil {
+        where = append(where, "word_count = ?")
+        args = append(args, *f.WordCount)
+    }
+    if f.ContainsCharacter != "" {
+        where = append(where, "instr(value, ?) > 0")
+        args = append(args, f.ContainsCharacter)
+    }
+    if f.HasPalindromicWords != nil {
+        // The list is stored as null when the palindrome analyzer is disabled
+        if *f.HasPalindromicWords {
+            where = append(where, "COALESCE(json_array_length(palindromic_words), 0) > 0")
+        } else {
+            where = append(where, "COALESCE(json_array_length(palindromic_words), 0) = 0")
+        }
+    }
+    if f.PalindromicWord != "" {
+        where = append(where, "EXISTS (SELECT 1 FROM json_each(palindromic_words) WHERE json_each.value = ?)")
+        args = append(args, f.PalindromicWord)
+    }
+    if f.MostCommonWord != "" {
+        where = append(where, "most_common_word = ?")
+        args = append(args, f.MostCommonWord)
+    }
+    if f.ContainsSecret != nil {
+        where = append(where, "contains_secret = ?")
+        args = append(args, *f.ContainsSecret)
+    }
+    if f.Script != "" {
+        where = append(where, "EXISTS (SELECT 1 FROM json_each(script_counts) WHERE json_each.key = ?)")
+        args = append(args, f.Script)
+    }
+    if f.Language != "" {
+        where = append(where, "language = ?")
+        args = append(args, f.Language)
+    }
+    if f.HasEmoji != nil {
+        if *f.HasEmoji {
+            where = append(where, "emoji_count > 0")
+        } else {
+            where = append(where, "emoji_count = 0")
+        }
+    }
+    if f.MixedScript != nil {
+        where = append(where, "mixed_script = ?")
+        args = append(args, *f.MixedScript)
+    }
+    for _, piiType := range f.PIITypes {
+        where = append(where, "EXISTS (SELECT 1 FROM json_each(pii_types) WHERE json_each.value = ?)")
+        args = append(args, piiType)
+    }
+    if f.ContainsWord != "" {
+        where = append(where, "EXISTS (SELECT 1 FROM json_each(word_frequency_map) WHERE json_each.key = ?)")
+        args = append(args, f.ContainsWord)
+    }
+    
+    // Range names come from the storage allowlist, so only columns we know about reach the SQL
+    for _, name := range storage.RangeFields() {
+        r, ok := f.Ranges[name]
+        if !ok {
+            continue
+        }
+        if r.Min != nil {
+            where = append(where, storage.RangeColumn(name)+" >= ?")
+            args = append(args, *r.Min)
+        }
+        if r.Max != nil {
+            where = append(where, storage.RangeColumn(name)+" <= ?")
+            args = append(args, *r.Max)
+        }
+    }
+    
+    return where, args
+}
+
+// DeleteString removes the row and its digests. The digests are deleted
+// explicitly as well, in case foreign keys are switched off in the DSN.
+func (r *SQLiteRepository) DeleteString(value string) (bool, error) {
+    return r.deleteBy("value", value)
+}
+
+// DeleteStringByID removes the row with the given primary key and its digests
+func (r *SQLiteRepository) DeleteStringByID(id string) (bool, error) {
+    return r.deleteBy("id", id)
+}
+
+// deleteBy removes the row whose column equals key; column is never user input
+func (r *SQLiteRepository) deleteBy(column, key string) (bool, error) {
+    var rowsAffected int64
+    err := runInTx(r.db, func(tx *sql.Tx) error {
+        _, err := tx.Exec("DELETE FROM string_hashes WHERE string_id IN (SELECT id FROM analyzed_strings WHERE "+column+" = ?)", key)
+        if err != nil {
+            return err
+        }
+        result, err := tx.Exec("DELETE FROM analyzed_strings WHERE "+column+" = ?", key)
+        if err != nil {
+            return err
+        }
+        rowsAffected, err = result.RowsAffected()
+        return err
+    })
+    if err != nil {
+        return false, err
+    }
+    
+    return rowsAffected > 0, nil
+}
+
+func (r *SQLiteRepository) StringExists(value string) (bool, error) {
+    var exists bool
+    query := "SELECT EXISTS(SELECT 1 FROM analyzed_strings WHERE value = ?)"
+    err := r.db.QueryRow(query, value).Scan(&exists)
+    return exists, err
+}
+
+// GetIdempotentResponse returns the unexpired response stored under key
+func (r *SQLiteRepository) GetIdempotentResponse(key string, now time.Time) (storage.IdempotentResponse, bool, error) {
+    var response storage.IdempotentResponse
+    var expiresAt int64
+    err := r.db.QueryRow("SELECT fingerprint, status, content_type, body, expires_at FROM idempotency_keys WHERE key = ? AND expires_at > ?", key, now.Unix()).
+        Scan(&response.Fingerprint, &response.Status, &response.ContentType, &response.Body, &expiresAt)
+    if err == sql.ErrNoRows {
+        return response, false, nil
+    }
+    if err != nil {
+        return response, false, err
+    }
+    response.ExpiresAt = time.Unix(expiresAt, 0)
+    return response, true, nil
+}
+
+// SaveIdempotentResponse records a response and purges expired ones
+func (r *SQLiteRepository) SaveIdempotentResponse(key string, response storage.IdempotentResponse, now time.Time) error {
+    // A nil slice would be bound as NULL, e.g. for a 204 without a body
+    if response.Body == nil {
+        response.Body = []byte{}
+    }
+    return runInTx(r.db, func(tx *sql.Tx) error {
+        if _, err := tx.Exec("DELETE FROM idempotency_keys WHERE expires_at <= ?", now.Unix()); err != nil {
+            return err
+        }
+        _, err := tx.Exec("INSERT OR REPLACE INTO idempotency_keys (key, fingerprint, status, content_type, body, expires_at) VALUES (?, ?, ?, ?, ?, ?)",
+            key, response.Fingerprint, response.Status, response.ContentType, response.Body, response.ExpiresAt.Unix())
+        return err
+    })
+}
+
+// Close closes the underlying database handle, checkpointing the WAL
+func (r *SQLiteRepository) Close() error {
+    return r.db.Close()
+}
@@ -0,0 +1,529 @@
+package database
+
+import (
+    "testing"
+    "time"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/storage"
+)
+
+// newTestRepository opens a throwaway SQLite file with the schema applied
+func newTestRepository(t *testing.T) *SQLiteRepository {
+    t.Helper()
+    db := openTestDB(t)
+    if err := Migrate(db); err != nil {
+        t.Fatalf("Failed to create schema: %v", err)
+    }
+    return NewSQLiteRepository(db)
+}
+
+// analyzeWith analyzes value with opts, failing the test on an error
+func analyzeWith(t *testing.T, value string, opts services.Options) models.AnalysisResult {
+    t.Helper()
+    result, err := services.AnalyzeStringWithOptions(value, opts)
+    if err != nil {
+        t.Fatalf("Failed to analyze %q: %v", value, err)
+    }
+    return result
+}
+
+// TestListStringsFilters tests that filters are applied in SQL
+func TestListStringsFilters(t *testing.T) {
+    repo := newTestRepository(t)
+    for _, value := range []string{"level", "hello", "noon", "hello world"} {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    isPalindrome := true
+    minLength := 5
+    page, err := repo.ListStrings(storage.Query{Filters: storage.Filters{IsPalindrome: &isPalindrome, MinLength: &minLength}})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 1 || page.Results[0].Value != "level" {
+        t.Errorf("Expected only 'level', got %+v", page.Results)
+    }
+    
+    page, err = repo.ListStrings(storage.Query{Filters: storage.Filters{ContainsCharacter: "w"}})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 1 || page.Results[0].Value != "hello world" {
+        t.Errorf("Expected only 'hello world', got %+v", page.Results)
+    }
+}
+
+// TestHasPalindromicWordsWithoutAnalyzer tests that a list stored as null,
+// as it is with the palindrome analyzer disabled, counts as empty like it
+// does in MemoryStorage
+func TestHasPalindromicWordsWithoutAnalyzer(t *testing.T) {
+    if err := services.DefaultRegistry.SetEnabled("palindrome", false); err != nil {
+        t.Fatalf("SetEnabled failed: %v", err)
+    }
+    defer services.DefaultRegistry.SetEnabled("palindrome", true)
+    
+    repo := newTestRepository(t)
+    memory := storage.NewMemoryStorage()
+    result := services.AnalyzeString("wow mom")
+    if result.PalindromicWords != nil {
+        t.Fatalf("Expected no palindromic words from a disabled analyzer, got %v", result.PalindromicWords)
+    }
+    for _, r := range []storage.StringRepository{repo, memory} {
+        if err := r.StoreString(result); err != nil {
+            t.Fatalf("StoreString failed: %v", err)
+        }
+    }
+    
+    for _, has := range []bool{true, false} {
+        query := storage.Query{Filters: storage.Filters{HasPalindromicWords: &has}}
+        sqlPage, err := repo.ListStrings(query)
+        if err != nil {
+            t.Fatalf("ListStrings failed: %v", err)
+        }
+        memoryPage, _ := memory.ListStrings(query)
+        if len(sqlPage.Results) != len(memoryPage.Results) || len(sqlPage.Results) != map[bool]int{true: 0, false: 1}[has] {
+            t.Errorf("has_palindromic_words=%v: SQLite returned %d rows, memory %d", has, len(sqlPage.Results), len(memoryPage.Results))
+        }
+    }
+}
+
+// TestListStringsPagination tests cursor pagination walks every row once
+func TestListStringsPagination(t *testing.T) {
+    repo := newTestRepository(t)
+    values := []string{"a", "b", "c", "d", "e"}
+    for _, value := range values {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    seen := make(map[string]bool)
+    query := storage.Query{Limit: 2}
+    for pages := 0; ; pages++ {
+        if pages > len(values) {
+            t.Fatal("Pagination did not terminate")
+        }
+        page, err := repo.ListStrings(query)
+        if err != nil {
+            t.Fatalf("ListStrings failed: %v", err)
+        }
+        for _, result := range page.Results {
+            if seen[result.Value] {
+                t.Errorf("Value %q returned twice", result.Value)
+            }
+            seen[result.Value] = true
+        }
+        if page.NextCursor == "" {
+            break
+        }
+        query.Cursor = page.NextCursor
+    }
+    if len(seen) != len(values) {
+        t.Errorf("Expected %d values, got %d", len(values), len(seen))
+    }
+}
+
+// TestListStringsSortedPagination tests keyset pagination over a descending sort with ties
+func TestListStringsSortedPagination(t *testing.T) {
+    repo := newTestRepository(t)
+    values := []string{"aa", "bb", "ccc", "d", "eeee", "ff"}
+    for _, value := range values {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    sort, err := storage.ParseSort("-length")
+    if err != nil {
+        t.Fatalf("ParseSort failed: %v", err)
+    }
+    query := storage.Query{Sort: sort, Limit: 2}
+    var lengths []int
+    for {
+        page, err := repo.ListStrings(query)
+        if err != nil {
+            t.Fatalf("ListStrings failed: %v", err)
+        }
+        for _, result := range page.Results {
+            lengths = append(lengths, result.Length)
+        }
+        if page.NextCursor == "" {
+            break
+        }
+        query.Cursor = page.NextCursor
+    }
+    
+    expected := []int{4, 3, 2, 2, 2, 1}
+    if len(lengths) != len(expected) {
+        t.Fatalf("Expected %v, got %v", expected, lengths)
+    }
+    for i := range expected {
+        if lengths[i] != expected[i] {
+            t.Fatalf("Expected %v, got %v", expected, lengths)
+        }
+    }
+}
+
+// TestSortByRangeFields tests that every range-filterable property can also
+// be sorted by, paging through the newer ones one row at a time
+func TestSortByRangeFields(t *testing.T) {
+    for _, name := range storage.RangeFields() {
+        if _, err := storage.ParseSort(name); err != nil {
+            t.Errorf("Range field %s is not sortable: %v", name, err)
+        }
+    }
+    
+    repo := newTestRepository(t)
+    for _, value := range []string{"hi", "The quick brown fox jumps over the lazy dog", "🎉🎉 party 🎉", "extraordinarily sophisticated vocabulary", "Der schnelle braune Fuchs springt über den faulen Hund"} {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    for _, spec := range []string{"-emoji_count", "syllable_count", "-average_word_length", "-language_confidence"} {
+        sort, err := storage.ParseSort(spec)
+        if err != nil {
+            t.Fatalf("ParseSort(%q) failed: %v", spec, err)
+        }
+        query := storage.Query{Sort: sort, Limit: 1}
+        var seen []models.AnalysisResult
+        for {
+            page, err := repo.ListStrings(query)
+            if err != nil {
+                t.Fatalf("ListStrings(%q) failed: %v", spec, err)
+            }
+            seen = append(seen, page.Results...)
+            if page.NextCursor == "" {
+                break
+            }
+            query.Cursor = page.NextCursor
+        }
+        if len(seen) != 5 {
+            t.Fatalf("Sort %q returned %d rows, want 5", spec, len(seen))
+        }
+        for i := 1; i < len(seen); i++ {
+            if sort.Compare(seen[i-1], seen[i]) > 0 {
+                t.Errorf("Sort %q out of order at %d: %q before %q", spec, i, seen[i-1].Value, seen[i].Value)
+            }
+        }
+    }
+}
+
+// TestListStringsRangeFilters tests min_/max_ range filters and palindromic word lookups
+func TestListStringsRangeFilters(t *testing.T) {
+    repo := newTestRepository(t)
+    for _, value := range []string{"racecar driver", "noon and level", "plain text"} {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    minLongest := 5.0
+    page, err := repo.ListStrings(storage.Query{Filters: storage.Filters{
+        Ranges: map[string]storage.Range{"longest_palindrome_length": {Min: &minLongest}},
+    }})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 2 {
+        t.Errorf("Expected 2 strings with a palindrome of 5+ runes, got %d", len(page.Results))
+    }
+    
+    page, err = repo.ListStrings(storage.Query{Filters: storage.Filters{PalindromicWord: "level"}})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 1 || page.Results[0].Value != "noon and level" {
+        t.Errorf("Expected only 'noon and level', got %+v", page.Results)
+    }
+}
+
+// TestListStringsWordFilters tests the most common word and contains word filters
+func TestListStringsWordFilters(t *testing.T) {
+    repo := newTestRepository(t)
+    for _, value := range []string{"the cat and the hat", "a dog and a cat", "the end"} {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    page, err := repo.ListStrings(storage.Query{Filters: storage.Filters{MostCommonWord: "the"}})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 1 || page.Results[0].Value != "the cat and the hat" {
+        t.Errorf("Expected only 'the cat and the hat', got %+v", page.Results)
+    }
+    
+    page, err = repo.ListStrings(storage.Query{Filters: storage.Filters{ContainsWord: "cat"}})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 2 {
+        t.Errorf("Expected 2 strings containing 'cat', got %d", len(page.Results))
+    }
+    
+    result, _, err := repo.GetString("a dog and a cat")
+    if err != nil || result.WordFrequencyMap["a"] != 2 || len(result.TopWords) != 4 {
+        t.Errorf("Word statistics did not round-trip: %+v, %v", result, err)
+    }
+}
+
+// TestListStringsReadabilityRanges tests min_/max_ filters on real-valued columns
+func TestListStringsReadabilityRanges(t *testing.T) {
+    repo := newTestRepository(t)
+    for _, value := range []string{"The cat sat on the mat.", "Institutional considerations necessitate comprehensive restructuring."} {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    minEase := 60.0
+    page, err := repo.ListStrings(storage.Query{Filters: storage.Filters{
+        Ranges: map[string]storage.Range{"flesch_reading_ease": {Min: &minEase}},
+    }})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 1 || page.Results[0].Value != "The cat sat on the mat." {
+        t.Errorf("Expected only the easy sentence, got %+v", page.Results)
+    }
+    
+    sort, _ := storage.ParseSort("-flesch_kincaid_grade")
+    page, err = repo.ListStrings(storage.Query{Sort: sort, Limit: 1})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 1 || page.Results[0].FleschKincaidGrade <= 10 || page.NextCursor == "" {
+        t.Errorf("Expected the dense sentence first with a next cursor, got %+v", page)
+    }
+    page, err = repo.ListStrings(storage.Query{Sort: sort, Cursor: page.NextCursor})
+    if err != nil || len(page.Results) != 1 || page.Results[0].Value != "The cat sat on the mat." {
+        t.Errorf("Expected the easy sentence on the second page, got %+v, %v", page.Results, err)
+    }
+}
+
+// TestListStringsPIITypes tests that every requested PII type must be present
+func TestListStringsPIITypes(t *testing.T) {
+    repo := newTestRepository(t)
+    for _, value := range []string{"ana@example.org", "ana@example.org from 10.0.0.1", "10.0.0.2"} {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    page, err := repo.ListStrings(storage.Query{Filters: storage.Filters{PIITypes: []string{services.PIIEmail, services.PIIIPAddress}}})
+    if err != nil {
+        t.Fatalf("ListStrings failed: %v", err)
+    }
+    if len(page.Results) != 1 || page.Results[0].RedactedValue != "[REDACTED:email] from [REDACTED:ip_address]" {
+        t.Errorf("Expected only the string with both types, got %+v", page.Results)
+    }
+}
+
+// TestListStringsScriptFilters tests the script and emoji filters in SQL
+func TestListStringsScriptFilters(t *testing.T) {
+    repo := newTestRepository(t)
+    for _, value := range []string{"привет 🎉", "hello", "gοοgle"} {
+        if err := repo.StoreString(services.AnalyzeString(value)); err != nil {
+            t.Fatalf("Failed to store %q: %v", value, err)
+        }
+    }
+    
+    hasEmoji, mixed := true, true
+    for _, tc := range []struct {
+        filters  storage.Filters
+        expected string
+    }{
+        {storage.Filters{Script: "Cyrillic"}, "привет 🎉"},
+        {storage.Filters{HasEmoji: &hasEmoji}, "привет 🎉"},
+        {storage.Filters{Script: "Greek", MixedScript: &mixed}, "gοοgle"},
+    } {
+        page, err := repo.ListStrings(storage.Query{Filters: tc.filters})
+        if err != nil {
+            t.Fatalf("ListStrings failed: %v", err)
+        }
+        if len(page.Results) != 1 || page.Results[0].Value != tc.expected {
+            t.Errorf("Filters %+v: expected only %q, got %+v", tc.filters, tc.expected, page.Results)
+        }
+    }
+}
+
+// TestFindByHash tests that digests are stored, loaded and removed with their string
+func TestFindByHash(t *testing.T) {
+    repo := newTestRepository(t)
+    result := analyzeWith(t, "hello", services.Options{Hashes: []string{"md5"}})
+    if err := repo.StoreString(result); err != nil {
+        t.Fatalf("Failed to store: %v", err)
+    }
+    
+    found, err := repo.FindByHash("md5", "5d41402abc4b2a76b9719d911017c592")
+    if err != nil || len(found) != 1 || found[0].Value != "hello" {
+        t.Fatalf("FindByHash = %+v, %v", found, err)
+    }
+    if found[0].Hashes["md5"] != result.Hashes["md5"] || found[0].Hashes["sha256"] != result.SHA256Hash {
+        t.Errorf("Hashes did not round-trip: %v", found[0].Hashes)
+    }
+    
+    if _, err := repo.DeleteString("hello"); err != nil {
+        t.Fatalf("DeleteString failed: %v", err)
+    }
+    var remaining int
+    if err := repo.db.QueryRow("SELECT COUNT(*) FROM string_hashes").Scan(&remaining); err != nil || remaining != 0 {
+        t.Errorf("Expected digests to be deleted, %d remain (%v)", remaining, err)
+    }
+}
+
+func TestStringByID(t *testing.T) {
+    repo := newTestRepository(t)
+    result := analyzeWith(t, "hello", services.Options{Hashes: []string{"md5"}})
+    if err := repo.StoreString(result); err != nil {
+        t.Fatalf("Failed to store: %v", err)
+    }
+    
+    found, exists, err := repo.GetStringByID(result.ID)
+    if err != nil || !exists || found.Value != "hello" || found.Hashes["md5"] == "" {
+        t.Fatalf("GetStringByID = %+v, %v, %v", found, exists, err)
+    }
+    
+    deleted, err := repo.DeleteStringByID(result.ID)
+    if err != nil || !deleted {
+        t.Fatalf("DeleteStringByID = %v, %v", deleted, err)
+    }
+    if _, exists, _ := repo.GetStringByID(result.ID); exists {
+        t.Error("Expected the string to be gone")
+    }
+    var remaining int
+    if err := repo.db.QueryRow("SELECT COUNT(*) FROM string_hashes").Scan(&remaining); err != nil || remaining != 0 {
+        t.Errorf("Expected digests to be deleted, %d remain (%v)", remaining, err)
+    }
+}
+
+func TestStoreStrings(t *testing.T) {
+    repo := newTestRepository(t)
+    if err := repo.StoreString(services.AnalyzeString("existing")); err != nil {
+        t.Fatalf("Failed to store: %v", err)
+    }
+    
+    batch := []models.AnalysisResult{
+        analyzeWith(t, "one", services.Options{Hashes: []string{"md5"}}),
+        services.AnalyzeString("existing"),
+        services.AnalyzeString("one"),
+    }
+    inserted, err := repo.StoreStrings(batch)
+    if err != nil {
+        t.Fatalf("StoreStrings failed: %v", err)
+    }
+    if !inserted[0] || inserted[1] || inserted[2] {
+        t.Errorf("Unexpected inserted flags %v", inserted)
+    }
+    
+    found, err := repo.FindByHash("md5", batch[0].Hashes["md5"])
+    if err != nil || len(found) != 1 {
+        t.Errorf("Expected the batch digests to be stored, got %v, %v", found, err)
+    }
+}
+
+func TestCreateOrGetAndUpsertString(t *testing.T) {
+    repo := newTestRepository(t)
+    first := analyzeWith(t, "Racecar", services.Options{PalindromeMode: services.PalindromeStrict})
+    
+    stored, created, err := repo.CreateOrGetString(first)
+    if err != nil || !created {
+        t.Fatalf("CreateOrGetString = %v, %v", created, err)
+    }
+    stored, created, err = repo.CreateOrGetString(services.AnalyzeString("Racecar"))
+    if err != nil || created || stored.IsPalindrome || stored.Hashes["sha256"] == "" {
+        t.Errorf("Expected the stored analysis back, got %+v, %v, %v", stored, created, err)
+    }
+    
+    updated, created, err := repo.UpsertString(analyzeWith(t, "Racecar", services.Options{Hashes: []string{"md5"}}))
+    if err != nil || created {
+        t.Fatalf("UpsertString = %v, %v", created, err)
+    }
+    if !updated.IsPalindrome || updated.ID != first.ID || updated.CreatedAt != first.CreatedAt {
+        t.Errorf("Expected a re-analysis keeping ID and created_at, got %+v", updated)
+    }
+    reread, _, _ := repo.GetString("Racecar")
+    if !reread.IsPalindrome || reread.Hashes["md5"] == "" {
+        t.Errorf("Upsert did not persist: %+v", reread)
+    }
+    
+    if _, created, err := repo.UpsertString(services.AnalyzeString("new")); err != nil || !created {
+        t.Errorf("Expected an upsert of a new value to create it, got %v, %v", created, err)
+    }
+}
+
+func TestUpsertStrings(t *testing.T) {
+    repo := newTestRepository(t)
+    first := analyzeWith(t, "Level", services.Options{PalindromeMode: services.PalindromeStrict})
+    if err := repo.StoreString(first); err != nil {
+        t.Fatalf("StoreString failed: %v", err)
+    }
+    
+    batch := []models.AnalysisResult{services.AnalyzeString("Level"), services.AnalyzeString("new"), services.AnalyzeString("new")}
+    created, err := repo.UpsertStrings(batch)
+    if err != nil {
+        t.Fatalf("UpsertStrings failed: %v", err)
+    }
+    if len(created) != 3 || created[0] || !created[1] || created[2] {
+        t.Errorf("Expected update, create, update; got %v", created)
+    }
+    reread, _, _ := repo.GetString("Level")
+    if !reread.IsPalindrome || reread.ID != first.ID || reread.CreatedAt != first.CreatedAt {
+        t.Errorf("Expected a re-analysis keeping ID and created_at, got %+v", reread)
+    }
+}
+
+func TestIdempotentResponses(t *testing.T) {
+    repo := newTestRepository(t)
+    now := time.Unix(1700000000, 0)
+    response := storage.IdempotentResponse{Fingerprint: "abc", Status: 204, Body: nil, ExpiresAt: now.Add(time.Minute)}
+    if err := repo.SaveIdempotentResponse("key", response, now); err != nil {
+        t.Fatalf("SaveIdempotentResponse failed: %v", err)
+    }
+    
+    got, found, err := repo.GetIdempotentResponse("key", now)
+    if err != nil || !found || got.Fingerprint != "abc" || got.Status != 204 || !got.ExpiresAt.Equal(response.ExpiresAt) {
+        t.Errorf("GetIdempotentResponse = %+v, %v, %v", got, found, err)
+    }
+    if _, found, _ := repo.GetIdempotentResponse("key", now.Add(time.Hour)); found {
+        t.Error("Expected an expired response to be ignored")
+    }
+    
+    // Saving later purges the expired row
+    if err := repo.SaveIdempotentResponse("other", response, now.Add(time.Hour)); err != nil {
+        t.Fatalf("SaveIdempotentResponse failed: %v", err)
+    }
+    var remaining int
+    repo.db.QueryRow("SELECT COUNT(*) FROM idempotency_keys").Scan(&remaining)
+    if remaining != 1 {
+        t.Errorf("Expected expired keys to be purged, %d rows remain", remaining)
+    }
+}
+
+func TestEachString(t *testing.T) {
+    repo := newTestRepository(t)
+    for _, value := range []string{"level", "hello", "racecar"} {
+        result := analyzeWith(t, value, services.Options{Hashes: []string{"md5"}})
+        if err := repo.StoreString(result); err != nil {
+            t.Fatalf("Failed to store: %v", err)
+        }
+    }
+    
+    isPalindrome := true
+    var values []string
+    err := repo.EachString(storage.Filters{IsPalindrome: &isPalindrome}, func(result models.AnalysisResult) error {
+        if result.Hashes["md5"] == "" || result.Hashes["sha256"] != result.SHA256Hash {
+            t.Errorf("Expected digests for %q, got %v", result.Value, result.Hashes)
+        }
+        values = append(values, result.Value)
+        return nil
+    })
+    if err != nil || len(values) != 2 {
+        t.Errorf("EachString visited %v, %v", values, err)
+    }
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "os"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// deleteReport is printed by the delete command
+type deleteReport struct {
+    Deleted  int      `json:"deleted"`
+    NotFound []string `json:"not_found"`
+}
+
+// runDelete implements "delete [flags] VALUE...". The arguments are values,
+// or IDs with -id; alternatively -filter removes every matching string.
+// Arguments that match nothing are listed in the report but are not an error.
+func runDelete(args []string) error {
+    flags := newFlagSet("delete", "VALUE... | -id ID... | -filter QUERY")
+    byID := flags.Bool("id", false, "treat the arguments as IDs rather than values")
+    filter := filterFlag(flags, "delete every string")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if (*filter == "") == (flags.NArg() == 0) {
+        flags.Usage()
+        return errors.New("delete needs either arguments or -filter")
+    }
+    
+    cfg, _, err := loadConfig()
+    if err != nil {
+        return err
+    }
+    repo, err := openStore(cfg)
+    if err != nil {
+        return err
+    }
+    defer repo.Close()
+    
+    keys := flags.Args()
+    deleteByID := *byID
+    if *filter != "" {
+        filters, err := parseFilterFlag(*filter)
+        if err != nil {
+            return err
+        }
+        // Collect first: SQLite may not allow writes while the cursor is open
+        err = repo.EachString(filters, func(result models.AnalysisResult) error {
+            keys = append(keys, result.ID)
+            return nil
+        })
+        if err != nil {
+            return err
+        }
+        deleteByID = true
+    }
+    
+    report := deleteReport{NotFound: []string{}}
+    for _, key := range keys {
+        var deleted bool
+        if deleteByID {
+            deleted, err = repo.DeleteStringByID(strings.ToLower(key))
+        } else {
+            deleted, err = repo.DeleteString(key)
+        }
+        if err != nil {
+            return err
+        }
+        if deleted {
+            report.Deleted++
+        } else {
+            report.NotFound = append(report.NotFound, key)
+        }
+    }
+    
+    encoder := json.NewEncoder(os.Stdout)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(report)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+    "bufio"
+    "compress/gzip"
+    "errors"
+    "io"
+    "log"
+    "os"
+    "path/filepath"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/transfer"
+)
+
+// runExport implements "export [flags]", the command-line counterpart of
+// GET /strings/export. Output goes to standard output unless -o names a
+// file, whose extension also selects the format; a .gz suffix compresses it.
+func runExport(args []string) error {
+    flags := newFlagSet("export", "")
+    format := flags.String("format", "", "jsonl, csv or parquet (default: from the -o extension, else jsonl)")
+    output := flags.String("o", "-", "file to write, - for standard output")
+    compress := flags.Bool("gzip", false, "gzip the output (implied by a .gz file name)")
+    filter := filterFlag(flags, "only export strings")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if flags.NArg() != 0 {
+        flags.Usage()
+        return errors.New("export takes no arguments")
+    }
+    
+    filters, err := parseFilterFlag(*filter)
+    if err != nil {
+        return err
+    }
+    name := strings.TrimSuffix(*output, ".gz")
+    gzipped := *compress || name != *output
+    exportFormat := transfer.FormatJSONL
+    if *format != "" {
+        if exportFormat, err = transfer.ParseFormat(*format); err != nil {
+            return err
+        }
+    } else if inferred, err := transfer.ParseFormat(strings.TrimPrefix(filepath.Ext(name), ".")); err == nil {
+        exportFormat = inferred
+    }
+    
+    cfg, _, err := loadConfig()
+    if err != nil {
+        return err
+    }
+    repo, err := openStore(cfg)
+    if err != nil {
+        return err
+    }
+    defer repo.Close()
+    
+    file := os.Stdout
+    if *output != "-" {
+        if file, err = os.Create(*output); err != nil {
+            return err
+        }
+        defer file.Close()
+    }
+    buffered := bufio.NewWriter(file)
+    var w io.Writer = buffered
+    var gz *gzip.Writer
+    if gzipped {
+        gz = gzip.NewWriter(buffered)
+        w = gz
+    }
+    
+    encoder := transfer.NewEncoder(exportFormat, w)
+    count := 0
+    err = repo.EachString(filters, func(result models.AnalysisResult) error {
+        count++
+        return encoder.Encode(result)
+    })
+    if err != nil {
+        return err
+    }
+    if err := encoder.Close(); err != nil {
+        return err
+    }
+    if gz != nil {
+        if err := gz.Close(); err != nil {
+            return err
+        }
+    }
+    if err := buffered.Flush(); err != nil {
+        return err
+    }
+    if *output != "-" {
+        if err := file.Close(); err != nil {
+            return err
+        }
+    }
+    log.Printf("Exported %d strings", count)
+    return nil
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+    "net/http"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/gin-gonic/gin"
+)
+
+// ListAnalyzersHandler reports the registered analyzers, their versions and
+// whether each one is enabled
+func ListAnalyzersHandler(c *gin.Context) {
+    analyzers := services.DefaultRegistry.Analyzers()
+    c.JSON(http.StatusOK, gin.H{
+        "data": analyzers,
+        "count": len(analyzers),
+    })
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/json"
+    "errors"
+    "io"
+    "net/http"
+    "strconv"
+    "sync"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/gin-gonic/gin"
+)
+
+// Per-item outcomes reported by POST /strings/batch
+const (
+    batchCreated  = "created"
+    batchConflict = "conflict"
+    batchInvalid  = "invalid"
+)
+
+// Defaults used when Settings leaves the batch limits unset
+const (
+    defaultBatchWorkers = 4
+    defaultMaxBatchSize = 1000
+)
+
+// errBatchTooLarge stops reading as soon as the limit is passed
+var errBatchTooLarge = errors.New("batch too large")
+
+// batchItem is one element of a batch: either a bare JSON string or an
+// object shaped like the POST /strings body
+type batchItem struct {
+    Value          json.RawMessage `json:"value"`
+    PalindromeMode string          `json:"palindrome_mode"`
+    SecretPolicy   string          `json:"secret_policy"`
+}
+
+// batchJob is an item handed to the workers, in the order it was read
+type batchJob struct {
+    index int
+    line  int
+    raw   json.RawMessage
+}
+
+// batchOutcome is what a worker reports back for one job; err is set when
+// a required analyzer failed
+type batchOutcome struct {
+    analyzed models.AnalysisResult
+    result   batchResult
+    err      error
+}
+
+// batchResult is the status of one item; Line is set for NDJSON bodies
+type batchResult struct {
+    Index          int              `json:"index"`
+    Line           int              `json:"line,omitempty"`
+    Status         string           `json:"status"`
+    ID             string           `json:"id,omitempty"`
+    Error          string           `json:"error,omitempty"`
+    SecretFindings []models.Finding `json:"secret_findings,omitempty"`
+}
+
+// PostBatchHandler analyzes many values in one request. The body is a JSON
+// array, or one value per line when sent as application/x-ndjson. Items are
+// handed to a bounded pool of workers as they are read, so analysis runs
+// while the body is still arriving, and stored in a single transaction once
+// it has been read; a bad or duplicate item is reported in its own status
+// instead of failing the batch.
+func (h *StringHandler) PostBatchHandler(c *gin.Context) {
+    hashes, err := services.ParseHashes(c.Query("hashes"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'hashes' (" + err.Error() + ")"})
+        return
+    }
+    
+    jobs := make(chan batchJob)
+    outcomes := make(chan batchOutcome)
+    var wg sync.WaitGroup
+    for w := 0; w < h.settings.BatchWorkers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for job := range jobs {
+                analyzed, result, err := h.analyzeBatchItem(job.raw, hashes)
+                result.Index, result.Line = job.index, job.line
+                outcomes <- batchOutcome{analyzed, result, err}
+            }
+        }()
+    }
+    
+    // Outcomes arrive out of order; only this goroutine touches the slices
+    // until collected is closed
+    var results []batchResult
+    var analyzed []models.AnalysisResult
+    var analyzeErr error
+    collected := make(chan struct{})
+    go func() {
+        defer close(collected)
+        for outcome := range outcomes {
+            if outcome.err != nil {
+                analyzeErr = outcome.err
+            }
+            for len(results) <= outcome.result.Index {
+                results = append(results, batchResult{})
+                analyzed = append(analyzed, models.AnalysisResult{})
+            }
+            results[outcome.result.Index] = outcome.result
+            analyzed[outcome.result.Index] = outcome.analyzed
+        }
+    }()
+    
+    count := 0
+    emit := func(raw json.RawMessage, line int) {
+        jobs <- batchJob{index: count, line: line, raw: raw}
+        count++
+    }
+    switch c.ContentType() {
+    case "application/x-ndjson", "application/ndjson", "application/jsonl":
+        err = readNDJSONBatch(c.Request.Body, h.settings.MaxBatchSize, emit)
+    default:
+        err = readJSONBatch(c.Request.Body, h.settings.MaxBatchSize, emit)
+    }
+    close(jobs)
+    wg.Wait()
+    close(outcomes)
+    <-collected
+    
+    if bodyTooLarge(c, err) {
+        return
+    }
+    if errors.Is(err, errBatchTooLarge) {
+        c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Batch exceeds " + strconv.Itoa(h.settings.MaxBatchSize) + " items"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body (expected a JSON array or NDJSON of values)"})
+        return
+    }
+    if analyzeErr != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze strings"})
+        return
+    }
+    if count == 0 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Batch is empty"})
+        return
+    }
+    
+    var toStore []models.AnalysisResult
+    var positions []int
+    for i, result := range results {
+        if result.Status != batchInvalid {
+            toStore = append(toStore, analyzed[i])
+            positions = append(positions, i)
+        }
+    }
+    
+    if len(toStore) > 0 {
+        inserted, err := h.repo.StoreStrings(toStore)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store strings"})
+            return
+        }
+        for j, i := range positions {
+            results[i].ID = toStore[j].ID
+            if inserted[j] {
+                results[i].Status = batchCreated
+            } else {
+                results[i].Status = batchConflict
+            }
+        }
+    }
+    
+    counts := map[string]int{}
+    for _, result := range results {
+        counts[result.Status]++
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "results":   results,
+        "count":     len(results),
+        "created":   counts[batchCreated],
+        "conflicts": counts[batchConflict],
+        "invalid":   counts[batchInvalid],
+    })
+}
+
+// analyzeBatchItem validates and analyzes one item. The status is left
+// empty for items that still have to be stored. An error means that a
+// required analyzer failed, which fails the whole batch.
+func (h *StringHandler) analyzeBatchItem(raw json.RawMessage, hashes []string) (models.AnalysisResult, batchResult, error) {
+    invalid := func(message string) (models.AnalysisResult, batchResult, error) {
+        return models.AnalysisResult{}, batchResult{Status: batchInvalid, Error: message}, nil
+    }
+    
+    if !json.Valid(raw) {
+        return invalid("Invalid JSON")
+    }
+    var item batchItem
+    if len(raw) > 0 && raw[0] == '"' {
+        item.Value = raw
+    } else if err := json.Unmarshal(raw, &item); err != nil || item.Value == nil || bytes.Equal(item.Value, []byte("null")) {
+        return invalid("Invalid item or missing 'value' field")
+    }
+    var value string
+    if err := json.Unmarshal(item.Value, &value); err != nil {
+        return invalid("Invalid data type for 'value' (must be string)")
+    }
+    
+    palindromeMode, err := services.ParsePalindromeMode(item.PalindromeMode)
+    if err != nil {
+        return invalid("Invalid value for 'palindrome_mode' (" + err.Error() + ")")
+    }
+    requestedPolicy, err := parseSecretPolicy(item.SecretPolicy)
+    if err != nil {
+        return invalid("Invalid value for 'secret_policy' (" + err.Error() + ")")
+    }
+    
+    opts := services.Options{PalindromeMode: palindromeMode, Hashes: hashes, IDHash: h.settings.IDHash}
+    result, rejected, err := h.analyze(value, opts, requestedPolicy)
+    if err != nil {
+        return result, batchResult{}, err
+    }
+    if rejected {
+        return result, batchResult{Status: batchInvalid, Error: "Value appears to contain secrets", SecretFindings: result.SecretFindings}, nil
+    }
+    return result, batchResult{}, nil
+}
+
+// readJSONBatch passes each item of a JSON array to emit as it is decoded,
+// stopping once there are more than max
+func readJSONBatch(body io.Reader, max int, emit func(item json.RawMessage, line int)) error {
+    dec := json.NewDecoder(body)
+    token, err := dec.Token()
+    if err != nil {
+        return err
+    }
+    if token != json.Delim('[') {
+        return errors.New("expected a JSON array")
+    }
+    for count := 0; dec.More(); count++ {
+        if count == max {
+            return errBatchTooLarge
+        }
+        var item json.RawMessage
+        if err := dec.Decode(&item); err != nil {
+            return err
+        }
+        emit(item, 0)
+    }
+    _, err = dec.Token()
+    return err
+}
+
+// readNDJSONBatch passes each non-blank line to emit as it is read, with
+// its 1-based line number, stopping once there are more than max. Lines
+// that are not valid JSON are passed on so that they are reported as
+// invalid items.
+func readNDJSONBatch(body io.Reader, max int, emit func(item json.RawMessage, line int)) error {
+    reader := bufio.NewReader(body)
+    count := 0
+    for line := 1; ; line++ {
+        text, err := reader.ReadBytes('\n')
+        if err != nil && err != io.EOF {
+            return err
+        }
+        if trimmed := bytes.TrimSpace(text); len(trimmed) > 0 {
+            if count == max {
+                return errBatchTooLarge
+            }
+            emit(json.RawMessage(trimmed), line)
+            count++
+        }
+        if err == io.EOF {
+            return nil
+        }
+    }
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+    "bytes"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/storage"
+    "github.com/gin-gonic/gin"
+)
+
+type batchResponse struct {
+    Results   []batchResult `json:"results"`
+    Created   int           `json:"created"`
+    Conflicts int           `json:"conflicts"`
+    Invalid   int           `json:"invalid"`
+}
+
+func decodeBatch(t *testing.T, w *httptest.ResponseRecorder) batchResponse {
+    t.Helper()
+    if w.Code != http.StatusOK {
+        t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+    }
+    var body batchResponse
+    if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+        t.Fatalf("Invalid JSON response: %v", err)
+    }
+    return body
+}
+
+// TestPostBatch tests per-item statuses for a JSON array body
+func TestPostBatch(t *testing.T) {
+    router := newTestRouter()
+    if w := doRequest(router, http.MethodPost, "/strings", `{"value": "existing"}`); w.Code != http.StatusCreated {
+        t.Fatalf("Expected 201, got %d", w.Code)
+    }
+    
+    w := doRequest(router, http.MethodPost, "/strings/batch", `["one", {"value": "two", "palindrome_mode": "strict"}, "one", "existing", 5, {"value": 7}, {"value": "x", "palindrome_mode": "bogus"}, {"value": null}, null]`)
+    body := decodeBatch(t, w)
+    
+    expected := []string{batchCreated, batchCreated, batchConflict, batchConflict, batchInvalid, batchInvalid, batchInvalid, batchInvalid, batchInvalid}
+    if len(body.Results) != len(expected) {
+        t.Fatalf("Expected %d results, got %+v", len(expected), body.Results)
+    }
+    for i, status := range expected {
+        if body.Results[i].Index != i || body.Results[i].Status != status {
+            t.Errorf("Item %d: got %+v, want status %s", i, body.Results[i], status)
+        }
+    }
+    if body.Created != 2 || body.Conflicts != 2 || body.Invalid != 5 {
+        t.Errorf("Unexpected totals %+v", body)
+    }
+    if body.Results[0].ID == "" || body.Results[0].ID != body.Results[2].ID {
+        t.Errorf("Expected the repeated value to report the same ID: %+v", body.Results)
+    }
+    
+    if w := doRequest(router, http.MethodGet, "/strings/two", ""); w.Code != http.StatusOK {
+        t.Errorf("Expected batch values to be stored, got %d", w.Code)
+    }
+}
+
+// TestPostBatchNDJSON tests the line-oriented variant and its line numbers
+func TestPostBatchNDJSON(t *testing.T) {
+    router := newTestRouter()
+    req := httptest.NewRequest(http.MethodPost, "/strings/batch", bytes.NewBufferString("\"alpha\"\n\n{\"value\": \"beta\"}\nnot json\n"))
+    req.Header.Set("Content-Type", "application/x-ndjson")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    body := decodeBatch(t, w)
+    
+    if len(body.Results) != 3 {
+        t.Fatalf("Expected 3 results, got %+v", body.Results)
+    }
+    lines := []int{1, 3, 4}
+    for i, line := range lines {
+        if body.Results[i].Line != line {
+            t.Errorf("Item %d: got line %d, want %d", i, body.Results[i].Line, line)
+        }
+    }
+    if body.Created != 2 || body.Results[2].Status != batchInvalid {
+        t.Errorf("Unexpected results %+v", body.Results)
+    }
+}
+
+// batchSignal receives the values the batch_signal analyzer sees
+var batchSignal = make(chan string, 16)
+
+// TestPostBatchNDJSONStreams tests that items are analyzed while the rest
+// of the body is still being sent
+func TestPostBatchNDJSONStreams(t *testing.T) {
+    // The analyzer stays registered, so it is only enabled for this test
+    services.Register(services.NewCustomAnalyzer("batch_signal", "1", func(input string) (interface{}, error) {
+        select {
+        case batchSignal <- input:
+        default:
+        }
+        return nil, nil
+    }))
+    if err := services.DefaultRegistry.SetEnabled("batch_signal", true); err != nil {
+        t.Fatalf("SetEnabled failed: %v", err)
+    }
+    defer services.DefaultRegistry.SetEnabled("batch_signal", false)
+    for len(batchSignal) > 0 {
+        <-batchSignal
+    }
+    
+    router := newTestRouter()
+    body, writer := io.Pipe()
+    req := httptest.NewRequest(http.MethodPost, "/strings/batch", body)
+    req.Header.Set("Content-Type", "application/x-ndjson")
+    w := httptest.NewRecorder()
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        router.ServeHTTP(w, req)
+    }()
+    
+    writer.Write([]byte("\"first\"\n"))
+    select {
+    case value := <-batchSignal:
+        if value != "first" {
+            t.Errorf("Expected the first item to be analyzed, got %q", value)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("The first item was not analyzed before the body ended")
+    }
+    writer.Write([]byte("\"second\"\n"))
+    writer.Close()
+    <-done
+    
+    if result := decodeBatch(t, w); result.Created != 2 {
+        t.Errorf("Expected both items to be stored, got %+v", result.Results)
+    }
+}
+
+// TestPostBatchLimits tests the size limit and malformed bodies
+func TestPostBatchLimits(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    router := gin.New()
+    h := NewStringHandler(storage.NewMemoryStorage(), Settings{BatchWorkers: 2, MaxBatchSize: 2})
+    router.POST("/strings/batch", h.PostBatchHandler)
+    
+    cases := map[string]int{
+        `["a", "b"]`:      http.StatusOK,
+        `["a", "b", "c"]`: http.StatusRequestEntityTooLarge,
+        `[]`:              http.StatusBadRequest,
+        `{"value": "a"}`:  http.StatusBadRequest,
+        `["a", `:          http.StatusBadRequest,
+    }
+    for payload, expected := range cases {
+        if w := doRequest(router, http.MethodPost, "/strings/batch", payload); w.Code != expected {
+            t.Errorf("POST %s returned %d, want %d: %s", payload, w.Code, expected, w.Body.String())
+        }
+    }
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+    "compress/gzip"
+    "errors"
+    "io"
+    "log"
+    "net/http"
+    "strings"
+    "time"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/transfer"
+    "github.com/gin-gonic/gin"
+)
+
+// ExportHandler streams every string matching the GET /strings filters as
+// JSON Lines, CSV or Parquet, gzip-compressed when the client accepts it.
+// Rows go straight from the storage cursor to the response, so the export
+// is never held in memory. Once streaming has started the status is sent,
+// so a failure is logged and the connection dropped before the body is
+// terminated. The server's write timeout is lifted, since a large export
+// can take far longer than a normal response.
+func (h *StringHandler) ExportHandler(c *gin.Context) {
+    format, err := transfer.ParseFormat(c.Query("format"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'format' (allowed: " + strings.Join(transfer.Formats(), ", ") + ")"})
+        return
+    }
+    filters, _, err := parseFilters(c.Request.URL.Query())
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    
+    liftDeadlines(c, false)
+    c.Header("Content-Type", format.ContentType())
+    c.Header("Content-Disposition", `attachment; filename="strings.`+string(format)+`"`)
+    c.Header("Vary", "Accept-Encoding")
+    
+    var out io.Writer = c.Writer
+    var gz *gzip.Writer
+    if acceptsGzip(c.GetHeader("Accept-Encoding")) {
+        c.Header("Content-Encoding", "gzip")
+        gz = gzip.NewWriter(c.Writer)
+        out = gz
+    }
+    c.Status(http.StatusOK)
+    
+    encoder := transfer.NewEncoder(format, out)
+    err = h.repo.EachString(filters, func(result models.AnalysisResult) error {
+        return encoder.Encode(result)
+    })
+    if err == nil {
+        err = encoder.Close()
+    }
+    if err == nil && gz != nil {
+        err = gz.Close()
+    }
+    if err != nil {
+        log.Println("Export failed:", err)
+        abortStream(c)
+    }
+}
+
+// abortStream drops the connection of a response that failed after its
+// status was sent. Ending the body normally, or writing the gzip trailer,
+// would make a truncated stream look complete.
+func abortStream(c *gin.Context) {
+    c.Abort()
+    conn, _, err := http.NewResponseController(c.Writer).Hijack()
+    if err != nil {
+        panic(http.ErrAbortHandler)
+    }
+    conn.Close()
+}
+
+// liftDeadlines clears the server's write deadline for this request, and
+// its read deadline too when read is set, for routes whose run time grows
+// with the data. Test recorders cannot set deadlines, which is not an error.
+func liftDeadlines(c *gin.Context, read bool) {
+    controller := http.NewResponseController(c.Writer)
+    err := controller.SetWriteDeadline(time.Time{})
+    if err == nil && read {
+        err = controller.SetReadDeadline(time.Time{})
+    }
+    if err != nil && !errors.Is(err, http.ErrNotSupported) {
+        log.Println("Failed to lift deadlines:", err)
+    }
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+    for _, part := range strings.Split(header, ",") {
+        coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+        if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+            continue
+        }
+        q := strings.ReplaceAll(params, " ", "")
+        return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+    }
+    return false
+}
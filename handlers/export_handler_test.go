@@ -0,0 +1,174 @@
+package handlers
+
+import (
+    "bytes"
+    "compress/gzip"
+    "encoding/csv"
+    "errors"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/storage"
+    "github.com/gin-gonic/gin"
+)
+
+// TestExportStrings tests filtering, formats and gzip negotiation
+func TestExportStrings(t *testing.T) {
+    router := newTestRouter()
+    for _, value := range []string{"level", "hello world", "racecar"} {
+        if w := doRequest(router, http.MethodPost, "/strings", `{"value": "`+value+`"}`); w.Code != http.StatusCreated {
+            t.Fatalf("Expected 201, got %d", w.Code)
+        }
+    }
+    
+    w := doRequest(router, http.MethodGet, "/strings/export?format=csv&is_palindrome=true", "")
+    if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+        t.Fatalf("Unexpected response %d %q", w.Code, w.Header().Get("Content-Type"))
+    }
+    records, err := csv.NewReader(w.Body).ReadAll()
+    if err != nil || len(records) != 3 {
+        t.Errorf("Expected a header and 2 palindromes, got %v, %v", records, err)
+    }
+    
+    req := httptest.NewRequest(http.MethodGet, "/strings/export", nil)
+    req.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+    rec := httptest.NewRecorder()
+    router.ServeHTTP(rec, req)
+    if rec.Header().Get("Content-Encoding") != "gzip" {
+        t.Fatalf("Expected a gzip response, got headers %v", rec.Header())
+    }
+    gz, err := gzip.NewReader(rec.Body)
+    if err != nil {
+        t.Fatalf("Invalid gzip body: %v", err)
+    }
+    data, _ := io.ReadAll(gz)
+    if lines := bytes.Count(data, []byte("\n")); lines != 3 {
+        t.Errorf("Expected 3 JSON lines, got %d: %s", lines, data)
+    }
+    
+    cases := map[string]int{
+        "/strings/export?format=xml":     http.StatusBadRequest,
+        "/strings/export?min_length=abc": http.StatusBadRequest,
+        "/strings/export?format=parquet": http.StatusOK,
+    }
+    for path, expected := range cases {
+        if w := doRequest(router, http.MethodGet, path, ""); w.Code != expected {
+            t.Errorf("GET %s returned %d, want %d", path, w.Code, expected)
+        }
+    }
+}
+
+// slowRepository delays every row it streams, standing in for a large export
+type slowRepository struct {
+    *storage.MemoryStorage
+    delay time.Duration
+}
+
+func (r slowRepository) EachString(filters storage.Filters, fn func(models.AnalysisResult) error) error {
+    return r.MemoryStorage.EachString(filters, func(result models.AnalysisResult) error {
+        time.Sleep(r.delay)
+        return fn(result)
+    })
+}
+
+// failingRepository streams a few rows and then fails, like a storage error
+// in the middle of an export
+type failingRepository struct {
+    *storage.MemoryStorage
+    after int
+}
+
+func (r failingRepository) EachString(filters storage.Filters, fn func(models.AnalysisResult) error) error {
+    rows := 0
+    return r.MemoryStorage.EachString(filters, func(result models.AnalysisResult) error {
+        if rows == r.after {
+            return errors.New("storage failed")
+        }
+        rows++
+        return fn(result)
+    })
+}
+
+// TestExportFailureBreaksResponse tests that an export failing part way
+// through cannot be read as a complete body
+func TestExportFailureBreaksResponse(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    repo := failingRepository{MemoryStorage: storage.NewMemoryStorage(), after: 2}
+    for _, value := range []string{"one", "two", "three", "four"} {
+        repo.StoreString(services.AnalyzeString(value))
+    }
+    router := gin.New()
+    router.Use(gin.Recovery())
+    router.GET("/strings/export", NewStringHandler(repo, Settings{}).ExportHandler)
+    server := httptest.NewServer(router)
+    defer server.Close()
+    
+    for _, path := range []string{"/strings/export", "/strings/export?format=csv"} {
+        for _, encoding := range []string{"", "gzip"} {
+            req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+            if encoding != "" {
+                req.Header.Set("Accept-Encoding", encoding)
+            }
+            resp, err := http.DefaultClient.Do(req)
+            if err != nil {
+                continue
+            }
+            data, err := io.ReadAll(resp.Body)
+            resp.Body.Close()
+            if err == nil {
+                t.Errorf("GET %s (%q) read a complete %d byte body", path, encoding, len(data))
+            }
+        }
+    }
+}
+
+// TestExportOutlivesWriteTimeout tests that an export is not cut off by the
+// server's write timeout
+func TestExportOutlivesWriteTimeout(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    repo := slowRepository{MemoryStorage: storage.NewMemoryStorage(), delay: 40 * time.Millisecond}
+    for _, value := range []string{"one", "two", "three", "four", "five"} {
+        repo.StoreString(services.AnalyzeString(value))
+    }
+    router := gin.New()
+    router.GET("/strings/export", NewStringHandler(repo, Settings{}).ExportHandler)
+    
+    server := httptest.NewUnstartedServer(router)
+    server.Config.WriteTimeout = 50 * time.Millisecond
+    server.Start()
+    defer server.Close()
+    
+    resp, err := http.Get(server.URL + "/strings/export")
+    if err != nil {
+        t.Fatalf("Request failed: %v", err)
+    }
+    defer resp.Body.Close()
+    data, err := io.ReadAll(resp.Body)
+    if err != nil {
+        t.Fatalf("Export was cut off after %d bytes: %v", len(data), err)
+    }
+    if lines := bytes.Count(data, []byte("\n")); lines != 5 {
+        t.Errorf("Expected 5 JSON lines, got %d", lines)
+    }
+}
+
+// TestAcceptsGzip tests Accept-Encoding parsing
+func TestAcceptsGzip(t *testing.T) {
+    cases := map[string]bool{
+        "":                false,
+        "gzip":            true,
+        "deflate, GZIP":   true,
+        "gzip;q=0":        false,
+        "br, gzip; q=0.5": true,
+        "x-gzip":          false,
+    }
+    for header, expected := range cases {
+        if acceptsGzip(header) != expected {
+            t.Errorf("acceptsGzip(%q) = %v, want %v", header, !expected, expected)
+        }
+    }
+}
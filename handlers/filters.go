@@ -0,0 +1,239 @@
+package handlers
+
+import (
+    "errors"
+    "net/url"
+    "strconv"
+    "slices"
+    "strings"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/storage"
+    "github.com/gin-gonic/gin"
+    "github.com/rivo/uniseg"
+)
+
+// parseFilters reads the GET /strings filter parameters. It also returns the
+// raw values that were supplied, echoed back to clients as filters_applied.
+func parseFilters(query url.Values) (storage.Filters, gin.H, error) {
+    filters := storage.Filters{}
+    filtersApplied := gin.H{}
+    
+    if isPalindromeStr := query.Get("is_palindrome"); isPalindromeStr != "" {
+        isPalindrome, err := strconv.ParseBool(isPalindromeStr)
+        if err != nil {
+            return filters, nil, errors.New("Invalid value for 'is_palindrome' (must be true or false)")
+        }
+        filters.IsPalindrome = &isPalindrome
+        filtersApplied["is_palindrome"] = isPalindromeStr
+    }
+    
+    intParams := []struct {
+        name   string
+        target **int
+    }{
+        {"min_length", &filters.MinLength},
+        {"max_length", &filters.MaxLength},
+        {"word_count", &filters.WordCount},
+    }
+    for _, param := range intParams {
+        raw := query.Get(param.name)
+        if raw == "" {
+            continue
+        }
+        value, err := strconv.Atoi(raw)
+        if err != nil {
+            return filters, nil, errors.New("Invalid value for '" + param.name + "' (must be integer)")
+        }
+        *param.target = &value
+        filtersApplied[param.name] = raw
+    }
+    
+    if containsChar := query.Get("contains_character"); containsChar != "" {
+        if uniseg.GraphemeClusterCount(containsChar) != 1 {
+            return filters, nil, errors.New("Invalid value for 'contains_character' (must be single character)")
+        }
+        filters.ContainsCharacter = containsChar
+        filtersApplied["contains_character"] = containsChar
+    }
+    
+    if hasWordsStr := query.Get("has_palindromic_words"); hasWordsStr != "" {
+        hasWords, err := strconv.ParseBool(hasWordsStr)
+        if err != nil {
+            return filters, nil, errors.New("Invalid value for 'has_palindromic_words' (must be true or false)")
+        }
+        filters.HasPalindromicWords = &hasWords
+        filtersApplied["has_palindromic_words"] = hasWordsStr
+    }
+    
+    if word := query.Get("palindromic_word"); word != "" {
+        filters.PalindromicWord = strings.ToLower(word)
+        filtersApplied["palindromic_word"] = word
+    }
+    
+    boolParams := []struct {
+        name   string
+        target **bool
+    }{
+        {"contains_secret", &filters.ContainsSecret},
+        {"has_emoji", &filters.HasEmoji},
+        {"mixed_script", &filters.MixedScript},
+    }
+    for _, param := range boolParams {
+        raw := query.Get(param.name)
+        if raw == "" {
+            continue
+        }
+        value, err := strconv.ParseBool(raw)
+        if err != nil {
+            return filters, nil, errors.New("Invalid value for '" + param.name + "' (must be true or false)")
+        }
+        *param.target = &value
+        filtersApplied[param.name] = raw
+    }
+    
+    if scriptName := query.Get("script"); scriptName != "" {
+        script, ok := services.LookupScript(scriptName)
+        if !ok {
+            return filters, nil, errors.New("Invalid value for 'script' (must be a Unicode script name such as Latin or Cyrillic)")
+        }
+        filters.Script = script
+        filtersApplied["script"] = scriptName
+    }
+    
+    if piiTypes := query.Get("pii_types"); piiTypes != "" {
+        known := services.PIITypes()
+        for _, piiType := range strings.Split(piiTypes, ",") {
+            piiType = strings.TrimSpace(piiType)
+            if !slices.Contains(known, piiType) {
+                return filters, nil, errors.New("Invalid value for 'pii_types' (allowed: " + strings.Join(known, ", ") + ")")
+            }
+            filters.PIITypes = append(filters.PIITypes, piiType)
+        }
+        filtersApplied["pii_types"] = piiTypes
+    }
+    
+    if language := query.Get("language"); language != "" {
+        code := strings.ToLower(language)
+        if _, ok := services.LanguageName(code); !ok {
+            return filters, nil, errors.New("Invalid value for 'language' (allowed: " + strings.Join(services.Languages(), ", ") + ")")
+        }
+        filters.Language = code
+        filtersApplied["language"] = language
+    }
+    
+    // Words are stored lower-cased, see services.Words
+    if word := query.Get("most_common_word"); word != "" {
+        filters.MostCommonWord = strings.ToLower(word)
+        filtersApplied["most_common_word"] = word
+    }
+    
+    if word := query.Get("contains_word"); word != "" {
+        filters.ContainsWord = strings.ToLower(word)
+        filtersApplied["contains_word"] = word
+    }
+    
+    if err := parseRanges(query, &filters, filtersApplied); err != nil {
+        return filters, nil, err
+    }
+    
+    return filters, filtersApplied, nil
+}
+
+// ParseFilterQuery reads the GET /strings filters from a query string such
+// as "is_palindrome=true&min_length=3", for callers outside HTTP. Unlike the
+// endpoints it refuses unknown or empty parameters, so that a typo cannot
+// widen the selection.
+func ParseFilterQuery(rawQuery string) (storage.Filters, error) {
+    values, err := url.ParseQuery(rawQuery)
+    if err != nil {
+        return storage.Filters{}, errors.New("Invalid filter query (" + err.Error() + ")")
+    }
+    filters, filtersApplied, err := parseFilters(values)
+    if err != nil {
+        return filters, err
+    }
+    for name := range values {
+        if _, applied := filtersApplied[name]; !applied {
+            return filters, errors.New("Unknown or empty filter '" + name + "'")
+        }
+    }
+    return filters, nil
+}
+
+// parseRanges reads min_<name> and max_<name> for every range-filterable property
+func parseRanges(query url.Values, filters *storage.Filters, filtersApplied gin.H) error {
+    for _, name := range storage.RangeFields() {
+        var r storage.Range
+        for _, bound := range []struct {
+            param  string
+            target **float64
+        }{
+            {"min_" + name, &r.Min},
+            {"max_" + name, &r.Max},
+        } {
+            raw := query.Get(bound.param)
+            if raw == "" {
+                continue
+            }
+            value, err := strconv.ParseFloat(raw, 64)
+            if err != nil {
+                return errors.New("Invalid value for '" + bound.param + "' (must be a number)")
+            }
+            *bound.target = &value
+            filtersApplied[bound.param] = raw
+        }
+        if r.Min == nil && r.Max == nil {
+            continue
+        }
+        if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+            return errors.New("Invalid range for '" + name + "' (min_" + name + " is greater than max_" + name + ")")
+        }
+        if filters.Ranges == nil {
+            filters.Ranges = make(map[string]storage.Range)
+        }
+        filters.Ranges[name] = r
+    }
+    return nil
+}
+
+// parseSort reads the sort parameter into the query. It leaves any sort
+// already on the query alone when the parameter is absent.
+func parseSort(c *gin.Context, query *storage.Query) error {
+    spec := c.Query("sort")
+    if spec == "" {
+        return nil
+    }
+    sort, err := storage.ParseSort(spec)
+    if err != nil {
+        return errors.New("Invalid value for 'sort' (" + err.Error() + ")")
+    }
+    query.Sort = sort
+    return nil
+}
+
+// parsePagination reads the limit and cursor parameters into the query. The
+// sort must already be set, since a cursor is only valid for its own sort.
+func parsePagination(c *gin.Context, query *storage.Query) error {
+    if limitStr := c.Query("limit"); limitStr != "" {
+        limit, err := strconv.Atoi(limitStr)
+        if err != nil || limit < 1 || limit > storage.MaxLimit {
+            return errors.New("Invalid value for 'limit' (must be integer between 1 and " + strconv.Itoa(storage.MaxLimit) + ")")
+        }
+        query.Limit = limit
+    }
+    
+    query.Cursor = c.Query("cursor")
+    if _, _, err := query.StartAfter(); err != nil {
+        return errors.New("Invalid value for 'cursor'")
+    }
+    
+    return nil
+}
+
+// nextCursor renders an empty cursor as JSON null
+func nextCursor(page storage.Page) interface{} {
+    if page.NextCursor == "" {
+        return nil
+    }
+    return page.NextCursor
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "time"
+    "github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds how long all dependency checks may take together
+const readinessTimeout = 2 * time.Second
+
+// HealthCheck is one named dependency probed by the readiness endpoint
+type HealthCheck struct {
+    Name  string
+    Check func(ctx context.Context) error
+}
+
+// HealthHandler serves the liveness and readiness endpoints
+type HealthHandler struct {
+    version   string
+    startedAt time.Time
+    checks    []HealthCheck
+}
+
+// NewHealthHandler builds the probes for the given build version and dependencies
+func NewHealthHandler(version string, checks ...HealthCheck) *HealthHandler {
+    return &HealthHandler{
+        version:   version,
+        startedAt: time.Now(),
+        checks:    checks,
+    }
+}
+
+func (h *HealthHandler) status(status string) gin.H {
+    return gin.H{
+        "status": status,
+        "service": "string-analyzer",
+        "version": h.version,
+        "uptime_seconds": int64(time.Since(h.startedAt).Seconds()),
+        "timestamp": time.Now().UTC().Format(time.RFC3339),
+    }
+}
+
+// LivenessHandler reports that the process is up. It never touches
+// dependencies, so a database outage does not get the process restarted.
+func (h *HealthHandler) LivenessHandler(c *gin.Context) {
+    c.JSON(http.StatusOK, h.status("healthy"))
+}
+
+// ReadinessHandler runs every dependency check and returns 503 if any fails,
+// so load balancers stop routing traffic here until it recovers.
+func (h *HealthHandler) ReadinessHandler(c *gin.Context) {
+    ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+    defer cancel()
+    
+    ready := true
+    checks := gin.H{}
+    for _, check := range h.checks {
+        if err := check.Check(ctx); err != nil {
+            ready = false
+            checks[check.Name] = gin.H{"status": "down", "error": err.Error()}
+            continue
+        }
+        checks[check.Name] = gin.H{"status": "up"}
+    }
+    
+    if !ready {
+        response := h.status("unavailable")
+        response["checks"] = checks
+        c.JSON(http.StatusServiceUnavailable, response)
+        return
+    }
+    
+    response := h.status("ready")
+    response["checks"] = checks
+    c.JSON(http.StatusOK, response)
+}
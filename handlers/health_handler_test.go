@@ -0,0 +1,36 @@
+package handlers
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "testing"
+    "github.com/gin-gonic/gin"
+)
+
+func newHealthRouter(checks ...HealthCheck) *gin.Engine {
+    gin.SetMode(gin.TestMode)
+    h := NewHealthHandler("test", checks...)
+    router := gin.New()
+    router.GET("/healthz", h.LivenessHandler)
+    router.GET("/readyz", h.ReadinessHandler)
+    return router
+}
+
+// TestReadiness tests that a failing dependency turns readiness into a 503
+func TestReadiness(t *testing.T) {
+    healthy := HealthCheck{Name: "database", Check: func(ctx context.Context) error { return nil }}
+    broken := HealthCheck{Name: "database", Check: func(ctx context.Context) error { return errors.New("database is closed") }}
+    
+    if w := doRequest(newHealthRouter(healthy), http.MethodGet, "/readyz", ""); w.Code != http.StatusOK {
+        t.Errorf("Expected 200 when dependencies are up, got %d", w.Code)
+    }
+    
+    router := newHealthRouter(broken)
+    if w := doRequest(router, http.MethodGet, "/readyz", ""); w.Code != http.StatusServiceUnavailable {
+        t.Errorf("Expected 503 when a dependency is down, got %d", w.Code)
+    }
+    if w := doRequest(router, http.MethodGet, "/healthz", ""); w.Code != http.StatusOK {
+        t.Errorf("Expected liveness to stay 200, got %d", w.Code)
+    }
+}
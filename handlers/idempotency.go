@@ -0,0 +1,146 @@
+package handlers
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "encoding/hex"
+    "io"
+    "log"
+    "net/http"
+    "sync"
+    "time"
+    "github.com/holladworld/string-analyzer/storage"
+    "github.com/gin-gonic/gin"
+)
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// Idempotency replays the recorded response when a request is retried with
+// the same Idempotency-Key header. Requests without the header pass through.
+type Idempotency struct {
+    store        storage.IdempotencyStore
+    ttl          time.Duration
+    maxBodyBytes int64
+    now          func() time.Time
+    
+    mutex    sync.Mutex
+    inFlight map[string]bool
+}
+
+// NewIdempotency keeps responses in store for ttl. Bodies are buffered to
+// fingerprint them, so they are capped at maxBodyBytes, the same limit as
+// the endpoints behind the middleware.
+func NewIdempotency(store storage.IdempotencyStore, ttl time.Duration, maxBodyBytes int64) *Idempotency {
+    return &Idempotency{store: store, ttl: ttl, maxBodyBytes: maxBodyBytes, now: time.Now, inFlight: map[string]bool{}}
+}
+
+// Middleware records the first response for each key and replays it for
+// retries of the same request. Reusing a key for a different request is
+// refused with 422, and a retry that arrives while the first attempt is
+// still running gets 409. Server errors are not recorded, so a retry after
+// one runs the request again.
+func (i *Idempotency) Middleware(c *gin.Context) {
+    key := c.GetHeader("Idempotency-Key")
+    if key == "" {
+        c.Next()
+        return
+    }
+    if len(key) > maxIdempotencyKeyLength {
+        c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'Idempotency-Key' (at most 255 characters)"})
+        return
+    }
+    
+    body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, i.maxBodyBytes))
+    if bodyTooLarge(c, err) {
+        c.Abort()
+        return
+    }
+    if err != nil {
+        c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+        return
+    }
+    c.Request.Body = io.NopCloser(bytes.NewReader(body))
+    fingerprint := requestFingerprint(c.Request, body)
+    
+    if !i.claim(key) {
+        c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still in progress"})
+        return
+    }
+    defer i.release(key)
+    
+    stored, found, err := i.store.GetIdempotentResponse(key, i.now())
+    if err != nil {
+        c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if found {
+        if stored.Fingerprint != fingerprint {
+            c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+            return
+        }
+        c.Header("Idempotent-Replayed", "true")
+        c.Data(stored.Status, stored.ContentType, stored.Body)
+        c.Abort()
+        return
+    }
+    
+    recorder := &recordingWriter{ResponseWriter: c.Writer}
+    c.Writer = recorder
+    c.Next()
+    
+    if status := recorder.Status(); status < http.StatusInternalServerError {
+        now := i.now()
+        err := i.store.SaveIdempotentResponse(key, storage.IdempotentResponse{
+            Fingerprint: fingerprint,
+            Status:      status,
+            ContentType: recorder.Header().Get("Content-Type"),
+            Body:        recorder.body.Bytes(),
+            ExpiresAt:   now.Add(i.ttl),
+        }, now)
+        if err != nil {
+            log.Println("Failed to record idempotent response:", err)
+        }
+    }
+}
+
+// claim marks key as in flight, reporting false if it already was
+func (i *Idempotency) claim(key string) bool {
+    i.mutex.Lock()
+    defer i.mutex.Unlock()
+    if i.inFlight[key] {
+        return false
+    }
+    i.inFlight[key] = true
+    return true
+}
+
+func (i *Idempotency) release(key string) {
+    i.mutex.Lock()
+    defer i.mutex.Unlock()
+    delete(i.inFlight, key)
+}
+
+// requestFingerprint identifies a request by method, path, query and body
+func requestFingerprint(r *http.Request, body []byte) string {
+    h := sha256.New()
+    io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+    h.Write(body)
+    return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body as it is written
+type recordingWriter struct {
+    gin.ResponseWriter
+    body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+    w.body.Write(data)
+    return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+    w.body.WriteString(s)
+    return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+    "bytes"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+    "github.com/holladworld/string-analyzer/storage"
+    "github.com/gin-gonic/gin"
+)
+
+func doIdempotentRequest(router *gin.Engine, method, path, body, key string) *httptest.ResponseRecorder {
+    req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Idempotency-Key", key)
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w
+}
+
+// TestIdempotencyKey tests replaying, key reuse and expiry
+func TestIdempotencyKey(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    repo := storage.NewMemoryStorage()
+    h := NewStringHandler(repo, Settings{})
+    idempotency := NewIdempotency(repo, time.Minute, 1<<20)
+    now := time.Date(2024, 1, 21, 10, 0, 0, 0, time.UTC)
+    idempotency.now = func() time.Time { return now }
+    router := gin.New()
+    router.POST("/strings", idempotency.Middleware, h.PostStringHandler)
+    router.DELETE("/strings/:string_value", h.DeleteStringHandler)
+    
+    first := doIdempotentRequest(router, http.MethodPost, "/strings", `{"value": "hello"}`, "key-1")
+    if first.Code != http.StatusCreated {
+        t.Fatalf("Expected 201, got %d: %s", first.Code, first.Body.String())
+    }
+    
+    // Deleting in between shows the retry is replayed rather than re-run
+    doRequest(router, http.MethodDelete, "/strings/hello", "")
+    retry := doIdempotentRequest(router, http.MethodPost, "/strings", `{"value": "hello"}`, "key-1")
+    if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+        t.Errorf("Expected the original response to be replayed, got %d: %s", retry.Code, retry.Body.String())
+    }
+    if retry.Header().Get("Idempotent-Replayed") != "true" {
+        t.Error("Expected the Idempotent-Replayed header on a replay")
+    }
+    
+    if w := doIdempotentRequest(router, http.MethodPost, "/strings", `{"value": "other"}`, "key-1"); w.Code != http.StatusUnprocessableEntity {
+        t.Errorf("Expected 422 for a reused key, got %d", w.Code)
+    }
+    
+    now = now.Add(2 * time.Minute)
+    if w := doIdempotentRequest(router, http.MethodPost, "/strings", `{"value": "other"}`, "key-1"); w.Code != http.StatusCreated {
+        t.Errorf("Expected an expired key to run the request again, got %d", w.Code)
+    }
+}
+
+// TestIdempotencyKeyInFlight tests that a retry racing the first attempt is refused
+func TestIdempotencyKeyInFlight(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    idempotency := NewIdempotency(storage.NewMemoryStorage(), time.Minute, 1<<20)
+    router := gin.New()
+    router.POST("/strings", idempotency.Middleware, func(c *gin.Context) {
+        c.Status(http.StatusNoContent)
+    })
+    
+    idempotency.claim("busy")
+    if w := doIdempotentRequest(router, http.MethodPost, "/strings", `{}`, "busy"); w.Code != http.StatusConflict {
+        t.Errorf("Expected 409 while the key is in flight, got %d", w.Code)
+    }
+    idempotency.release("busy")
+    if w := doIdempotentRequest(router, http.MethodPost, "/strings", `{}`, "busy"); w.Code != http.StatusNoContent {
+        t.Errorf("Expected the request to run once released, got %d", w.Code)
+    }
+}
+
+// TestBodyLimit tests that oversized bodies get 413, whether or not they
+// carry an Idempotency-Key
+func TestBodyLimit(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    repo := storage.NewMemoryStorage()
+    h := NewStringHandler(repo, Settings{})
+    router := gin.New()
+    router.POST("/strings", LimitBody(32), NewIdempotency(repo, time.Minute, 32).Middleware, h.PostStringHandler)
+    router.POST("/strings/batch", LimitBody(32), h.PostBatchHandler)
+    
+    large := `{"value": "` + strings.Repeat("a", 64) + `"}`
+    if w := doIdempotentRequest(router, http.MethodPost, "/strings", large, "big"); w.Code != http.StatusRequestEntityTooLarge {
+        t.Errorf("Expected 413 with an Idempotency-Key, got %d: %s", w.Code, w.Body.String())
+    }
+    if w := doRequest(router, http.MethodPost, "/strings", large); w.Code != http.StatusRequestEntityTooLarge {
+        t.Errorf("Expected 413 without an Idempotency-Key, got %d: %s", w.Code, w.Body.String())
+    }
+    if w := doRequest(router, http.MethodPost, "/strings/batch", `["`+strings.Repeat("a", 64)+`"]`); w.Code != http.StatusRequestEntityTooLarge {
+        t.Errorf("Expected 413 for a large batch, got %d: %s", w.Code, w.Body.String())
+    }
+    if w := doIdempotentRequest(router, http.MethodPost, "/strings", `{"value": "ok"}`, "small"); w.Code != http.StatusCreated {
+        t.Errorf("Expected a small body to pass, got %d: %s", w.Code, w.Body.String())
+    }
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+    "errors"
+    "io"
+    "net/http"
+    "path/filepath"
+    "strings"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/transfer"
+    "github.com/gin-gonic/gin"
+)
+
+// ImportHandler stores every value of an uploaded file, sent either as the
+// raw body or as the "file" field of a multipart form. Without ?format the
+// format is inferred from the file name or content type. The response is a
+// report of created, updated, skipped and failed lines; lines that fail do
+// not stop the import. The server's read and write timeouts are lifted, as
+// an upload is read and stored for as long as it takes.
+func (h *StringHandler) ImportHandler(c *gin.Context) {
+    onDuplicate, err := transfer.ParseDuplicatePolicy(c.Query("on_duplicate"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'on_duplicate' (" + err.Error() + ")"})
+        return
+    }
+    palindromeMode, err := services.ParsePalindromeMode(c.Query("palindrome_mode"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'palindrome_mode' (" + err.Error() + ")"})
+        return
+    }
+    hashes, err := services.ParseHashes(c.Query("hashes"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'hashes' (" + err.Error() + ")"})
+        return
+    }
+    
+    liftDeadlines(c, true)
+    var source io.Reader = c.Request.Body
+    inferredFormat := transfer.ImportFormatFor(c.ContentType())
+    if c.ContentType() == "multipart/form-data" {
+        file, name, err := multipartFile(c.Request)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart body (expected a 'file' field)"})
+            return
+        }
+        source = file
+        inferredFormat = transfer.ImportFormatFor(filepath.Ext(name))
+    }
+    
+    format := inferredFormat
+    if name := c.Query("format"); name != "" {
+        if format, err = transfer.ParseImportFormat(name); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'format' (allowed: " + strings.Join(transfer.ImportFormats(), ", ") + ")"})
+            return
+        }
+    }
+    
+    report, err := transfer.Import(h.repo, source, transfer.ImportOptions{
+        Format:       format,
+        Column:       c.Query("column"),
+        OnDuplicate:  onDuplicate,
+        Analysis:     services.Options{PalindromeMode: palindromeMode, Hashes: hashes, IDHash: h.settings.IDHash},
+        SecretPolicy: h.settings.SecretPolicy,
+    })
+    if errors.Is(err, transfer.ErrMissingColumn) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        // Earlier chunks are already stored, so the partial report is returned too
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Import failed", "report": report})
+        return
+    }
+    
+    c.JSON(http.StatusOK, report)
+}
+
+// multipartFile returns the "file" part of a multipart body and its file name
+func multipartFile(r *http.Request) (io.Reader, string, error) {
+    reader, err := r.MultipartReader()
+    if err != nil {
+        return nil, "", err
+    }
+    for {
+        part, err := reader.NextPart()
+        if err != nil {
+            return nil, "", err
+        }
+        if part.FormName() == "file" {
+            return part, part.FileName(), nil
+        }
+    }
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+    "bytes"
+    "encoding/json"
+    "mime/multipart"
+    "net/http"
+    "net/http/httptest"
+    "io"
+    "testing"
+    "time"
+    "github.com/holladworld/string-analyzer/storage"
+    "github.com/holladworld/string-analyzer/transfer"
+    "github.com/gin-gonic/gin"
+)
+
+func decodeReport(t *testing.T, w *httptest.ResponseRecorder) transfer.ImportReport {
+    t.Helper()
+    if w.Code != http.StatusOK {
+        t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+    }
+    var report transfer.ImportReport
+    if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+        t.Fatalf("Invalid JSON response: %v", err)
+    }
+    return report
+}
+
+// TestImportStrings tests raw and multipart uploads and format inference
+func TestImportStrings(t *testing.T) {
+    router := newTestRouter()
+    report := decodeReport(t, doRequest(router, http.MethodPost, "/strings/import", "level\nhello\n\nlevel\n"))
+    if report.Created != 2 || report.Skipped != 1 || report.Failed != 0 {
+        t.Errorf("Unexpected text report %+v", report)
+    }
+    
+    var body bytes.Buffer
+    form := multipart.NewWriter(&body)
+    part, _ := form.CreateFormFile("file", "values.csv")
+    part.Write([]byte("id,value\n1,racecar\n2,hello\n"))
+    form.Close()
+    req := httptest.NewRequest(http.MethodPost, "/strings/import?on_duplicate=update", &body)
+    req.Header.Set("Content-Type", form.FormDataContentType())
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    if report := decodeReport(t, w); report.Created != 1 || report.Updated != 1 {
+        t.Errorf("Unexpected CSV report %+v", report)
+    }
+    
+    req = httptest.NewRequest(http.MethodPost, "/strings/import", bytes.NewBufferString("{\"value\": \"noon\"}\n{\"value\": 1}\n"))
+    req.Header.Set("Content-Type", "application/x-ndjson")
+    w = httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    if report := decodeReport(t, w); report.Created != 1 || report.Failed != 1 || report.Errors[0].Line != 2 {
+        t.Errorf("Unexpected JSONL report %+v", report)
+    }
+    
+    if w := doRequest(router, http.MethodGet, "/strings/racecar", ""); w.Code != http.StatusOK {
+        t.Errorf("Expected imported values to be stored, got %d", w.Code)
+    }
+}
+
+// TestImportStringsErrors tests invalid parameters and a missing CSV column
+func TestImportStringsErrors(t *testing.T) {
+    router := newTestRouter()
+    for _, path := range []string{
+        "/strings/import?format=xml",
+        "/strings/import?on_duplicate=replace",
+        "/strings/import?format=csv&column=text",
+    } {
+        if w := doRequest(router, http.MethodPost, path, "value\nlevel\n"); w.Code != http.StatusBadRequest {
+            t.Errorf("POST %s returned %d, want 400: %s", path, w.Code, w.Body.String())
+        }
+    }
+}
+
+// TestImportOutlivesServerTimeouts tests that a slow upload is not cut off
+// by the server's read and write timeouts
+func TestImportOutlivesServerTimeouts(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    router := gin.New()
+    router.POST("/strings/import", NewStringHandler(storage.NewMemoryStorage(), Settings{}).ImportHandler)
+    server := httptest.NewUnstartedServer(router)
+    server.Config.ReadTimeout = 50 * time.Millisecond
+    server.Config.WriteTimeout = 50 * time.Millisecond
+    server.Start()
+    defer server.Close()
+    
+    body, upload := io.Pipe()
+    go func() {
+        for _, value := range []string{"one", "two", "three", "four", "five"} {
+            time.Sleep(30 * time.Millisecond)
+            io.WriteString(upload, value+"\n")
+        }
+        upload.Close()
+    }()
+    resp, err := http.Post(server.URL+"/strings/import", "text/plain", body)
+    if err != nil {
+        t.Fatalf("Request failed: %v", err)
+    }
+    defer resp.Body.Close()
+    var report transfer.ImportReport
+    if err := json.NewDecoder(resp.Body).Decode(&report); err != nil || resp.StatusCode != http.StatusOK || report.Created != 5 {
+        t.Errorf("Unexpected response %d %+v, %v", resp.StatusCode, report, err)
+    }
+}
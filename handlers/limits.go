@@ -0,0 +1,28 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "github.com/gin-gonic/gin"
+)
+
+// LimitBody caps request bodies at max bytes. Reading past the cap fails
+// with *http.MaxBytesError, which the handlers behind it answer with 413.
+func LimitBody(max int64) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, max)
+        c.Next()
+    }
+}
+
+// bodyTooLarge answers 413 when err comes from reading past a body cap and
+// reports whether it did
+func bodyTooLarge(c *gin.Context, err error) bool {
+    var maxErr *http.MaxBytesError
+    if !errors.As(err, &maxErr) {
+        return false
+    }
+    c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body exceeds " + strconv.FormatInt(maxErr.Limit, 10) + " bytes"})
+    return true
+}
@@ -1,23 +1,65 @@
 package handlers
 
 import (
+    "encoding/base64"
+    "errors"
     "net/http"
     "strconv"
     "strings"
     "regexp"
     "reflect"
+    "unicode/utf8"
     "github.com/holladworld/string-analyzer/models"
     "github.com/holladworld/string-analyzer/services"
-    "github.com/holladworld/string-analyzer/database"
+    "github.com/holladworld/string-analyzer/storage"
     "github.com/gin-gonic/gin"
 )
 
-func PostStringHandler(c *gin.Context) {
+// StringHandler serves the /strings endpoints against an injected repository
+type StringHandler struct {
+    repo     storage.StringRepository
+    settings Settings
+}
+
+// Settings holds the server-wide behaviour of the /strings endpoints. The
+// zero value stores everything as submitted.
+type Settings struct {
+    // SecretPolicy applies to values the secrets analyzer flags; requests
+    // may only make it stricter
+    SecretPolicy services.SecretPolicy
+    // IDHash names the digest used as the ID of new strings
+    IDHash string
+    // BatchWorkers bounds how many values of a batch are analyzed at once
+    BatchWorkers int
+    // MaxBatchSize is the largest batch accepted
+    MaxBatchSize int
+}
+
+// NewStringHandler builds the handlers on top of the given storage backend
+func NewStringHandler(repo storage.StringRepository, settings Settings) *StringHandler {
+    if settings.SecretPolicy == "" {
+        settings.SecretPolicy = services.SecretPolicyAllow
+    }
+    if settings.BatchWorkers < 1 {
+        settings.BatchWorkers = defaultBatchWorkers
+    }
+    if settings.MaxBatchSize < 1 {
+        settings.MaxBatchSize = defaultMaxBatchSize
+    }
+    return &StringHandler{repo: repo, settings: settings}
+}
+
+func (h *StringHandler) PostStringHandler(c *gin.Context) {
     var request struct {
-        Value interface{} `json:"value" binding:"required"`
+        Value          interface{} `json:"value" binding:"required"`
+        PalindromeMode string      `json:"palindrome_mode"`
+        SecretPolicy   string      `json:"secret_policy"`
     }
     
     if err := c.ShouldBindJSON(&request); err != nil {
+        if bodyTooLarge(c, err) {
+            return
+        }
         c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body or missing 'value' field"})
         return
     }
@@ -29,144 +71,367 @@ func PostStringHandler(c *gin.Context) {
     
     stringValue := request.Value.(string)
     
-    exists, err := database.StringExists(stringValue)
+    palindromeMode, err := services.ParsePalindromeMode(request.PalindromeMode)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'palindrome_mode' (" + err.Error() + ")"})
         return
     }
-    if exists {
-        c.JSON(http.StatusConflict, gin.H{"error": "String already exists in the system"})
+    
+    requestedPolicy, err := parseSecretPolicy(request.SecretPolicy)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'secret_policy' (" + err.Error() + ")"})
         return
     }
     
-    result := services.AnalyzeString(stringValue)
+    hashes, err := services.ParseHashes(c.Query("hashes"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'hashes' (" + err.Error() + ")"})
+        return
+    }
     
-    err = database.StoreString(result)
+    opts := services.Options{PalindromeMode: palindromeMode, Hashes: hashes, IDHash: h.settings.IDHash}
+    result, rejected, err := h.analyze(stringValue, opts, requestedPolicy)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store string"})
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze string"})
+        return
+    }
+    if rejected {
+        c.JSON(http.StatusUnprocessableEntity, gin.H{
+            "error": "Value appears to contain secrets",
+            "secret_findings": result.SecretFindings,
+        })
         return
     }
     
-    c.JSON(http.StatusCreated, gin.H{
-        "id":    result.ID,
-        "value": result.Value,
-        "properties": gin.H{
-            "length": result.Length,
-            "is_palindrome": result.IsPalindrome,
-            "unique_characters": result.UniqueCharacters,
-            "word_count": result.WordCount,
-            "sha256_hash": result.SHA256Hash,
-            "character_frequency_map": result.CharacterFrequencyMap,
-        },
-        "created_at": result.CreatedAt,
-    })
+    // Posting a value that is already stored returns the stored analysis, so
+    // retries and concurrent duplicates succeed without special-casing
+    stored, created, err := h.repo.CreateOrGetString(result)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store string"})
+        return
+    }
+    status := http.StatusOK
+    if created {
+        status = http.StatusCreated
+    }
+    c.JSON(status, stringResponse(stored))
 }
 
-func GetStringHandler(c *gin.Context) {
-    requestedValue := c.Param("string_value")
+// PutStringHandler re-analyzes a value and stores the result, replacing an
+// earlier analysis of the same value. The optional body takes the same
+// palindrome_mode and secret_policy fields as POST /strings.
+func (h *StringHandler) PutStringHandler(c *gin.Context) {
+    stringValue, ok := pathValue(c)
+    if !ok {
+        return
+    }
     
-    result, exists, err := database.GetString(requestedValue)
+    var request struct {
+        PalindromeMode string `json:"palindrome_mode"`
+        SecretPolicy   string `json:"secret_policy"`
+    }
+    if c.Request.ContentLength != 0 {
+        if err := c.ShouldBindJSON(&request); err != nil {
+            if bodyTooLarge(c, err) {
+                return
+            }
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+            return
+        }
+    }
+    
+    palindromeMode, err := services.ParsePalindromeMode(request.PalindromeMode)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'palindrome_mode' (" + err.Error() + ")"})
         return
     }
-    if !exists {
-        c.JSON(http.StatusNotFound, gin.H{"error": "String does not exist in the system"})
+    
+    requestedPolicy, err := parseSecretPolicy(request.SecretPolicy)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'secret_policy' (" + err.Error() + ")"})
         return
     }
     
-    c.JSON(http.StatusOK, gin.H{
+    hashes, err := services.ParseHashes(c.Query("hashes"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'hashes' (" + err.Error() + ")"})
+        return
+    }
+    
+    opts := services.Options{PalindromeMode: palindromeMode, Hashes: hashes, IDHash: h.settings.IDHash}
+    result, rejected, err := h.analyze(stringValue, opts, requestedPolicy)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze string"})
+        return
+    }
+    if rejected {
+        c.JSON(http.StatusUnprocessableEntity, gin.H{
+            "error": "Value appears to contain secrets",
+            "secret_findings": result.SecretFindings,
+        })
+        return
+    }
+    
+    stored, created, err := h.repo.UpsertString(result)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store string"})
+        return
+    }
+    status := http.StatusOK
+    if created {
+        status = http.StatusCreated
+    }
+    c.JSON(status, stringResponse(stored))
+}
+
+// analyze runs the analyzers and applies the stricter of the server and
+// requested secret policies. Secrets are dealt with before any duplicate
+// check so that a redacted value is compared with what would be stored.
+// rejected reports that the value must not be stored at all, and an error
+// that a required analyzer failed.
+func (h *StringHandler) analyze(value string, opts services.Options, requested services.SecretPolicy) (result models.AnalysisResult, rejected bool, err error) {
+    return services.AnalyzeWithPolicy(value, opts, h.settings.SecretPolicy.Stricter(requested))
+}
+
+// parseSecretPolicy validates a requested policy. As with SECRET_POLICY,
+// redacting or rejecting needs the findings of the secrets analyzer.
+func parseSecretPolicy(name string) (services.SecretPolicy, error) {
+    policy, err := services.ParseSecretPolicy(name)
+    if err == nil && policy != services.SecretPolicyAllow && !services.DefaultRegistry.Enabled("secrets") {
+        return "", errors.New(string(policy) + " needs the secrets analyzer, which is disabled")
+    }
+    return policy, err
+}
+
+// stringResponse is the body returned for a single analyzed string
+func stringResponse(result models.AnalysisResult) gin.H {
+    return gin.H{
         "id":    result.ID,
         "value": result.Value,
-        "properties": gin.H{
-            "length": result.Length,
-            "is_palindrome": result.IsPalindrome,
-            "unique_characters": result.UniqueCharacters,
-            "word_count": result.WordCount,
-            "sha256_hash": result.SHA256Hash,
-            "character_frequency_map": result.CharacterFrequencyMap,
-        },
+        "properties": properties(result),
+        "analyzer_versions": result.AnalyzerVersions,
         "created_at": result.CreatedAt,
-    })
+    }
 }
 
-func GetAllStringsHandler(c *gin.Context) {
-    // Get query parameters
-    isPalindromeStr := c.Query("is_palindrome")
-    minLengthStr := c.Query("min_length")
-    maxLengthStr := c.Query("max_length")
-    wordCountStr := c.Query("word_count")
-    containsChar := c.Query("contains_character")
-    
-    // Validate query parameters
-    if isPalindromeStr != "" {
-        if _, err := strconv.ParseBool(isPalindromeStr); err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'is_palindrome' (must be true or false)"})
-            return
+// propertyGroups renders the typed fields each built-in analyzer fills
+var propertyGroups = []struct {
+    analyzer string
+    render   func(result models.AnalysisResult, props gin.H)
+}{
+    {"length", func(result models.AnalysisResult, props gin.H) {
+        props["length"] = result.Length
+        props["byte_length"] = result.ByteLength
+        props["rune_count"] = result.RuneCount
+        props["grapheme_count"] = result.GraphemeCount
+    }},
+    {"words", func(result models.AnalysisResult, props gin.H) {
+        props["word_count"] = result.WordCount
+    }},
+    {"palindrome", func(result models.AnalysisResult, props gin.H) {
+        props["is_palindrome"] = result.IsPalindrome
+        props["palindrome_mode"] = result.PalindromeMode
+        props["longest_palindrome"] = gin.H{
+            "value": result.LongestPalindrome,
+            "start": result.LongestPalindromeStart,
+            "end": result.LongestPalindromeEnd,
+            "length": result.LongestPalindromeLength,
+        }
+        props["distinct_palindromes"] = result.DistinctPalindromes
+        props["palindromic_words"] = result.PalindromicWords
+    }},
+    {"frequency", func(result models.AnalysisResult, props gin.H) {
+        props["unique_characters"] = result.UniqueCharacters
+        props["character_frequency_map"] = result.CharacterFrequencyMap
+    }},
+    {"ngrams", func(result models.AnalysisResult, props gin.H) {
+        props["word_frequency_map"] = result.WordFrequencyMap
+        props["word_bigrams"] = result.WordBigrams
+        props["character_bigrams"] = result.CharacterBigrams
+        props["character_trigrams"] = result.CharacterTrigrams
+        props["top_words"] = result.TopWords
+        props["most_common_word"] = result.MostCommonWord
+        props["hapax_legomena"] = result.HapaxLegomena
+    }},
+    {"readability", func(result models.AnalysisResult, props gin.H) {
+        props["sentence_count"] = result.SentenceCount
+        props["syllable_count"] = result.SyllableCount
+        props["average_word_length"] = result.AverageWordLength
+        props["flesch_reading_ease"] = result.FleschReadingEase
+        props["flesch_kincaid_grade"] = result.FleschKincaidGrade
+        props["lexical_diversity"] = result.LexicalDiversity
+    }},
+    {"entropy", func(result models.AnalysisResult, props gin.H) {
+        props["shannon_entropy"] = result.ShannonEntropy
+        props["normalized_entropy"] = result.NormalizedEntropy
+        props["compression_ratio"] = result.CompressionRatio
+    }},
+    {"secrets", func(result models.AnalysisResult, props gin.H) {
+        props["contains_secret"] = result.ContainsSecret
+        props["secret_findings"] = result.SecretFindings
+    }},
+    {"pii", func(result models.AnalysisResult, props gin.H) {
+        props["pii_findings"] = result.PIIFindings
+        props["pii_types"] = result.PIITypes
+        props["redacted_value"] = result.RedactedValue
+    }},
+    {"scripts", func(result models.AnalysisResult, props gin.H) {
+        props["category_counts"] = result.CategoryCounts
+        props["script_counts"] = result.ScriptCounts
+        props["emoji_count"] = result.EmojiCount
+        props["mixed_script"] = result.MixedScript
+    }},
+    {"language", func(result models.AnalysisResult, props gin.H) {
+        props["language"] = result.Language
+        props["language_confidence"] = result.LanguageConfidence
+    }},
+    {"hash", func(result models.AnalysisResult, props gin.H) {
+        props["sha256_hash"] = result.SHA256Hash
+        props["hashes"] = result.Hashes
+    }},
+}
+
+// properties renders the output of every analyzer that ran on the result.
+// Rows stored before the analyzer registry have no versions recorded and get
+// all built-in groups. Custom analyzer output is merged in by name, without
+// overriding built-in keys.
+func properties(result models.AnalysisResult) gin.H {
+    props := gin.H{}
+    legacy := len(result.AnalyzerVersions) == 0
+    for _, group := range propertyGroups {
+        if _, ran := result.AnalyzerVersions[group.analyzer]; ran || legacy {
+            group.render(result, props)
         }
     }
-    
-    if minLengthStr != "" {
-        if _, err := strconv.Atoi(minLengthStr); err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'min_length' (must be integer)"})
-            return
+    for name, value := range result.CustomProperties {
+        if _, taken := props[name]; !taken {
+            props[name] = value
         }
     }
-    
-    if maxLengthStr != "" {
-        if _, err := strconv.Atoi(maxLengthStr); err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'max_length' (must be integer)"})
-            return
+    return props
+}
+
+// pathValue reads the :string_value segment. With ?encoding=base64url the
+// segment is the URL-safe base64 of the value (padding optional), which lets
+// clients address values containing slashes or other awkward characters.
+func pathValue(c *gin.Context) (string, bool) {
+    value := c.Param("string_value")
+    switch c.Query("encoding") {
+    case "":
+        return value, true
+    case "base64url":
+        decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+        if err != nil || !utf8.Valid(decoded) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid base64url value"})
+            return "", false
         }
+        return string(decoded), true
+    default:
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'encoding' (allowed: base64url)"})
+        return "", false
+    }
+}
+
+func (h *StringHandler) GetStringHandler(c *gin.Context) {
+    requestedValue, ok := pathValue(c)
+    if !ok {
+        return
     }
     
-    if wordCountStr != "" {
-        if _, err := strconv.Atoi(wordCountStr); err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'word_count' (must be integer)"})
-            return
-        }
+    result, exists, err := h.repo.GetString(requestedValue)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if !exists {
+        c.JSON(http.StatusNotFound, gin.H{"error": "String does not exist in the system"})
+        return
+    }
+    
+    c.JSON(http.StatusOK, stringResponse(result))
+}
+
+// GetStringByIDHandler looks a string up by its ID, which works for any value
+func (h *StringHandler) GetStringByIDHandler(c *gin.Context) {
+    result, exists, err := h.repo.GetStringByID(strings.ToLower(c.Param("id")))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if !exists {
+        c.JSON(http.StatusNotFound, gin.H{"error": "String does not exist in the system"})
+        return
     }
     
-    if containsChar != "" && len(containsChar) != 1 {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid value for 'contains_character' (must be single character)"})
+    c.JSON(http.StatusOK, stringResponse(result))
+}
+
+// GetStringByHashHandler looks strings up by digest. It returns a list because
+// checksums such as crc32 can match more than one string.
+func (h *StringHandler) GetStringByHashHandler(c *gin.Context) {
+    algorithm := strings.ToLower(c.Param("algo"))
+    if _, err := services.ParseHashes(algorithm); err != nil || algorithm == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hash algorithm (allowed: " + strings.Join(services.HashAlgorithms(), ", ") + ")"})
         return
     }
+    digest := strings.ToLower(c.Param("digest"))
     
-    allStrings, err := database.GetAllStrings()
+    results, err := h.repo.FindByHash(algorithm, digest)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
         return
     }
+    if len(results) == 0 {
+        c.JSON(http.StatusNotFound, gin.H{"error": "No string with this " + algorithm + " digest"})
+        return
+    }
     
-    filteredStrings := make([]models.AnalysisResult, 0)
+    data := make([]gin.H, len(results))
+    for i, result := range results {
+        data[i] = stringResponse(result)
+    }
+    c.JSON(http.StatusOK, gin.H{"data": data, "count": len(results)})
+}
+
+func (h *StringHandler) GetAllStringsHandler(c *gin.Context) {
+    filters, filtersApplied, err := parseFilters(c.Request.URL.Query())
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
     
-    // Apply filters
-    for _, str := range allStrings {
-        if !applyFilters(str, isPalindromeStr, minLengthStr, maxLengthStr, wordCountStr, containsChar) {
-            continue
-        }
-        filteredStrings = append(filteredStrings, str)
+    query := storage.Query{Filters: filters}
+    if err := parseSort(c, &query); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err := parsePagination(c, &query); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
     }
     
-    // Build response
-    filtersApplied := gin.H{}
-    if isPalindromeStr != "" { filtersApplied["is_palindrome"] = isPalindromeStr }
-    if minLengthStr != "" { filtersApplied["min_length"] = minLengthStr }
-    if maxLengthStr != "" { filtersApplied["max_length"] = maxLengthStr }
-    if wordCountStr != "" { filtersApplied["word_count"] = wordCountStr }
-    if containsChar != "" { filtersApplied["contains_character"] = containsChar }
+    page, err := h.repo.ListStrings(query)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
     
     c.JSON(http.StatusOK, gin.H{
-        "data": filteredStrings,
-        "count": len(filteredStrings),
+        "data": page.Results,
+        "count": len(page.Results),
         "filters_applied": filtersApplied,
+        "sort_applied": query.Sort.String(),
+        "next_cursor": nextCursor(page),
     })
 }
 
-func DeleteStringHandler(c *gin.Context) {
-    requestedValue := c.Param("string_value")
+func (h *StringHandler) DeleteStringHandler(c *gin.Context) {
+    requestedValue, ok := pathValue(c)
+    if !ok {
+        return
+    }
     
-    deleted, err := database.DeleteString(requestedValue)
+    deleted, err := h.repo.DeleteString(requestedValue)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
         return
@@ -179,15 +444,30 @@ func DeleteStringHandler(c *gin.Context) {
     c.Status(http.StatusNoContent)
 }
 
-func NaturalLanguageFilterHandler(c *gin.Context) {
-    query := c.Query("query")
-    if query == "" {
+// DeleteStringByIDHandler removes a string by its ID
+func (h *StringHandler) DeleteStringByIDHandler(c *gin.Context) {
+    deleted, err := h.repo.DeleteStringByID(strings.ToLower(c.Param("id")))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if !deleted {
+        c.JSON(http.StatusNotFound, gin.H{"error": "String does not exist in the system"})
+        return
+    }
+    
+    c.Status(http.StatusNoContent)
+}
+
+func (h *StringHandler) NaturalLanguageFilterHandler(c *gin.Context) {
+    naturalQuery := c.Query("query")
+    if naturalQuery == "" {
         c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'query' is required"})
         return
     }
     
     // Parse natural language
-    filters := parseNaturalLanguage(query)
+    filters, sort := parseNaturalLanguage(naturalQuery)
     
     // Check for conflicting filters
     if filters.MinLength != nil && filters.MaxLength != nil && *filters.MinLength > *filters.MaxLength {
@@ -195,90 +475,37 @@ func NaturalLanguageFilterHandler(c *gin.Context) {
         return
     }
     
-    allStrings, err := database.GetAllStrings()
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+    // An explicit sort parameter takes precedence over one read from the query
+    query := storage.Query{Filters: filters, Sort: sort}
+    if err := parseSort(c, &query); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err := parsePagination(c, &query); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
     }
     
-    filteredStrings := make([]models.AnalysisResult, 0)
-    
-    for _, str := range allStrings {
-        if !applyFilters(str, 
-            boolToString(filters.IsPalindrome),
-            intToString(filters.MinLength),
-            intToString(filters.MaxLength),
-            intToString(filters.WordCount),
-            filters.ContainsCharacter) {
-            continue
-        }
-        filteredStrings = append(filteredStrings, str)
+    page, err := h.repo.ListStrings(query)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
     }
     
     c.JSON(http.StatusOK, gin.H{
-        "data": filteredStrings,
-        "count": len(filteredStrings),
+        "data": page.Results,
+        "count": len(page.Results),
         "interpreted_query": gin.H{
-            "original": query,
+            "original": naturalQuery,
             "parsed_filters": filters,
+            "parsed_sort": sort.String(),
         },
+        "next_cursor": nextCursor(page),
     })
 }
 
-// Helper functions
-func applyFilters(str models.AnalysisResult, isPalindromeStr, minLengthStr, maxLengthStr, wordCountStr, containsChar string) bool {
-    // Palindrome filter
-    if isPalindromeStr != "" {
-        wanted, _ := strconv.ParseBool(isPalindromeStr)
-        if str.IsPalindrome != wanted {
-            return false
-        }
-    }
-    
-    // Min length filter
-    if minLengthStr != "" {
-        minLen, _ := strconv.Atoi(minLengthStr)
-        if str.Length < minLen {
-            return false
-        }
-    }
-    
-    // Max length filter
-    if maxLengthStr != "" {
-        maxLen, _ := strconv.Atoi(maxLengthStr)
-        if str.Length > maxLen {
-            return false
-        }
-    }
-    
-    // Word count filter
-    if wordCountStr != "" {
-        wordCount, _ := strconv.Atoi(wordCountStr)
-        if str.WordCount != wordCount {
-            return false
-        }
-    }
-    
-    // Contains character filter
-    if containsChar != "" && len(containsChar) > 0 {
-        if !strings.Contains(str.Value, containsChar) {
-            return false
-        }
-    }
-    
-    return true
-}
-
-type NaturalLanguageFilters struct {
-    IsPalindrome      *bool
-    MinLength         *int
-    MaxLength         *int
-    WordCount         *int
-    ContainsCharacter string
-}
-
-func parseNaturalLanguage(query string) NaturalLanguageFilters {
-    filters := NaturalLanguageFilters{}
+func parseNaturalLanguage(query string) (storage.Filters, storage.Sort) {
+    filters := storage.Filters{Ranges: make(map[string]storage.Range)}
     query = strings.ToLower(query)
     
     // Palindrome detection
@@ -342,19 +569,123 @@ func parseNaturalLanguage(query string) NaturalLanguageFilters {
         }
     }
     
-    return filters
+    // Most common word, e.g. "where the most common word is 'the'"
+    if matches := mostCommonWordPattern.FindStringSubmatch(query); matches != nil {
+        filters.MostCommonWord = strings.Trim(matches[1], "'")
+    }
+    
+    // Personal data, e.g. "strings containing emails"
+    for _, entry := range naturalPIIPhrases {
+        if entry.pattern.MatchString(query) {
+            filters.PIITypes = append(filters.PIITypes, entry.piiType)
+        }
+    }
+    
+    // Language, e.g. "french strings" or "strings in german"
+    if matches := languagePattern.FindStringSubmatch(query); matches != nil {
+        filters.Language = naturalLanguageCodes[matches[1]]
+    }
+    
+    // Entropy detection, e.g. "high entropy strings longer than 20 characters".
+    // Phrases like "least random" only set the order, see naturalSortPhrases.
+    highEntropy := strings.Contains(query, "high entropy")
+    for _, match := range randomPattern.FindAllStringSubmatch(query, -1) {
+        highEntropy = highEntropy || match[1] == ""
+    }
+    if highEntropy {
+        min := highEntropyBits
+        filters.Ranges["shannon_entropy"] = storage.Range{Min: &min}
+    } else if strings.Contains(query, "low entropy") {
+        max := lowEntropyBits
+        filters.Ranges["shannon_entropy"] = storage.Range{Max: &max}
+    }
+    
+    return filters, parseNaturalSort(query)
+}
+
+// naturalPIIPhrases maps mentions of personal data onto pii_types values.
+// Phrases match whole words, so "ssn" does not fire inside "classnames".
+var naturalPIIPhrases = []struct {
+    pattern *regexp.Regexp
+    piiType string
+}{
+    {wordsPattern("email", "e-mail"), services.PIIEmail},
+    {wordsPattern("ip address"), services.PIIIPAddress},
+    {wordsPattern("credit card", "card number"), services.PIICreditCard},
+    {wordsPattern("iban", "bank account"), services.PIIIBAN},
+    {wordsPattern("national id", "social security", "ssn", "national insurance"), services.PIINationalID},
+    {wordsPattern("phone", "telephone"), services.PIIPhone},
 }
 
-func boolToString(b *bool) string {
-    if b == nil {
-        return ""
+// wordsPattern matches any of phrases as whole words, optionally plural
+func wordsPattern(phrases ...string) *regexp.Regexp {
+    quoted := make([]string, len(phrases))
+    for i, phrase := range phrases {
+        quoted[i] = regexp.QuoteMeta(phrase)
     }
-    return strconv.FormatBool(*b)
+    return regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)(?:s|es)?\b`)
 }
 
-func intToString(i *int) string {
-    if i == nil {
-        return ""
+// naturalLanguageCodes maps language names onto codes, and languagePattern
+// finds the first name mentioned in a query
+var naturalLanguageCodes, languagePattern = func() (map[string]string, *regexp.Regexp) {
+    codes := make(map[string]string)
+    names := make([]string, 0)
+    for _, code := range services.Languages() {
+        name, _ := services.LanguageName(code)
+        codes[name] = code
+        names = append(names, name)
+    }
+    return codes, regexp.MustCompile(`\b(` + strings.Join(names, "|") + `)\b`)
+}()
+
+// Entropy thresholds in bits per character for natural language queries.
+// English prose sits around 3.5-4; random tokens of 20+ characters above 4.
+const (
+    highEntropyBits = 4.0
+    lowEntropyBits  = 2.5
+)
+
+// randomPattern finds requests for random-looking strings. A match that
+// captures a negation, as in "least random strings", asks for the opposite.
+var randomPattern = regexp.MustCompile(`\b(?:(least|less|not|non)[\s-]+)?(?:most random|random (?:strings?|tokens?|values?|text))\b`)
+
+var mostCommonWordPattern = regexp.MustCompile(`most (?:common|frequent) word (?:is )?["'‘“]?([\p{L}\p{N}']+)`)
+
+// naturalSortPhrases maps ordering phrases onto sort fields. The first match
+// for each field wins, and fields are applied in the order they appear here.
+var naturalSortPhrases = []struct {
+    phrases []string
+    field   storage.SortField
+}{
+    {[]string{"longest", "longer first"}, storage.SortField{Field: "length", Desc: true}},
+    {[]string{"shortest", "shorter first"}, storage.SortField{Field: "length"}},
+    {[]string{"most words"}, storage.SortField{Field: "word_count", Desc: true}},
+    {[]string{"fewest words", "least words"}, storage.SortField{Field: "word_count"}},
+    {[]string{"most unique"}, storage.SortField{Field: "unique_characters", Desc: true}},
+    {[]string{"fewest unique", "least unique"}, storage.SortField{Field: "unique_characters"}},
+    {[]string{"highest entropy", "most random"}, storage.SortField{Field: "shannon_entropy", Desc: true}},
+    {[]string{"lowest entropy", "least random"}, storage.SortField{Field: "shannon_entropy"}},
+    {[]string{"newest", "latest", "most recent", "recently added"}, storage.SortField{Field: "created_at", Desc: true}},
+    {[]string{"oldest", "earliest"}, storage.SortField{Field: "created_at"}},
+}
+
+// parseNaturalSort turns phrases like "longest first" or "newest strings"
+// into a sort spec. The query must already be lower-cased.
+func parseNaturalSort(query string) storage.Sort {
+    var sort storage.Sort
+    used := make(map[string]bool)
+    for _, entry := range naturalSortPhrases {
+        if used[entry.field.Field] {
+            continue
+        }
+        for _, phrase := range entry.phrases {
+            if strings.Contains(query, phrase) {
+                sort = append(sort, entry.field)
+                used[entry.field.Field] = true
+                break
+            }
+        }
     }
-    return strconv.Itoa(*i)
+    return sort
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/transfer"
+)
+
+// runImport implements "import [flags] FILE". It loads the same
+// configuration as the server and imports FILE, or standard input for "-",
+// straight into storage, printing the report as JSON. Failed lines make
+// the command exit non-zero after everything else was imported.
+func runImport(args []string) error {
+    flags := newFlagSet("import", "FILE (- for standard input)")
+    format := flags.String("format", "", "text, csv or jsonl (default: from the file extension, else text)")
+    column := flags.String("column", transfer.DefaultImportColumn, "CSV column holding the values")
+    onDuplicate := flags.String("on-duplicate", "skip", "skip or update values that are already stored")
+    palindromeMode := flags.String("palindrome-mode", "", "palindrome normalization, as palindrome_mode on POST /strings")
+    hashes := flags.String("hashes", "", "extra digests to compute, comma-separated")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if flags.NArg() != 1 {
+        flags.Usage()
+        return errors.New("import needs exactly one FILE")
+    }
+    path := flags.Arg(0)
+    
+    opts := transfer.ImportOptions{Format: transfer.ImportFormatFor(filepath.Ext(path)), Column: *column}
+    var err error
+    if *format != "" {
+        if opts.Format, err = transfer.ParseImportFormat(*format); err != nil {
+            return err
+        }
+    }
+    if opts.OnDuplicate, err = transfer.ParseDuplicatePolicy(*onDuplicate); err != nil {
+        return err
+    }
+    if opts.Analysis.PalindromeMode, err = services.ParsePalindromeMode(*palindromeMode); err != nil {
+        return err
+    }
+    if opts.Analysis.Hashes, err = services.ParseHashes(*hashes); err != nil {
+        return err
+    }
+    
+    cfg, secretPolicy, err := loadConfig()
+    if err != nil {
+        return err
+    }
+    opts.Analysis.IDHash = cfg.Analysis.PrimaryIDHash
+    opts.SecretPolicy = secretPolicy
+    
+    var input io.Reader = os.Stdin
+    if path != "-" {
+        file, err := os.Open(path)
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        input = file
+    }
+    
+    repo, err := openStore(cfg)
+    if err != nil {
+        return err
+    }
+    defer repo.Close()
+    
+    report, importErr := transfer.Import(repo, input, opts)
+    encoder := json.NewEncoder(os.Stdout)
+    encoder.SetIndent("", "  ")
+    if err := encoder.Encode(report); err != nil {
+        return err
+    }
+    if importErr != nil {
+        return importErr
+    }
+    if report.Failed > 0 {
+        return fmt.Errorf("%d lines could not be imported", report.Failed)
+    }
+    return nil
+}
@@ -1,43 +1,179 @@
 package main
 
 import (
+    "context"
+    "errors"
+    "flag"
     "log"
+    "net/http"
     "os"
+    "os/signal"
+    "syscall"
+    "github.com/holladworld/string-analyzer/config"
     "github.com/holladworld/string-analyzer/handlers"
     "github.com/holladworld/string-analyzer/database"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/storage"
     "github.com/gin-gonic/gin"
 )
 
+// version is the build version, set with -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
-    // Initialize database
-    err := database.Init()
+    err := runCommand(os.Args[1:])
+    if errors.Is(err, flag.ErrHelp) {
+        return
+    }
+    if err != nil {
+        log.Fatal(err)
+    }
+}
+
+// loadConfig reads the configuration shared by the server and the
+// subcommands and applies its analyzer settings
+func loadConfig() (config.Config, services.SecretPolicy, error) {
+    cfg, err := config.Load()
+    if err != nil {
+        return cfg, "", err
+    }
+    
+    // Analyzer names are only known once everything is registered, so they are checked here
+    for _, name := range cfg.Analysis.DisabledAnalyzers {
+        if err := services.DefaultRegistry.SetEnabled(name, false); err != nil {
+            return cfg, "", errors.New("invalid configuration: ANALYZERS_DISABLED: " + err.Error())
+        }
+    }
+    
+    // Redacting or rejecting relies on the findings of the secrets analyzer
+    secretPolicy := services.SecretPolicy(cfg.Analysis.SecretPolicy)
+    if secretPolicy != services.SecretPolicyAllow && !services.DefaultRegistry.Enabled("secrets") {
+        return cfg, "", errors.New("invalid configuration: SECRET_POLICY=" + cfg.Analysis.SecretPolicy + " needs the secrets analyzer, which ANALYZERS_DISABLED switches off")
+    }
+    return cfg, secretPolicy, nil
+}
+
+// run serves until SIGINT or SIGTERM, then drains in-flight requests within
+// the configured deadline and closes the storage backend before returning.
+func run() error {
+    cfg, secretPolicy, err := loadConfig()
+    if err != nil {
+        return err
+    }
+    
+    repo, err := newRepository(cfg)
     if err != nil {
-        log.Fatal("Failed to connect to database:", err)
+        return errors.New("Failed to initialize storage: " + err.Error())
     }
+    defer func() {
+        if err := repo.Close(); err != nil {
+            log.Println("Failed to close storage:", err)
+        }
+    }()
     
     router := gin.Default()
     
-    // Improved health check endpoint
-    router.GET("/health", func(c *gin.Context) {
-        // Simple response that always works
-        c.JSON(200, gin.H{
-            "status": "healthy",
-            "service": "string-analyzer",
-            "timestamp": "2024-01-21T10:00:00Z",
-        })
-    })
+    // Liveness and readiness probes; /health is kept for existing monitors
+    healthHandler := handlers.NewHealthHandler(version, readinessChecks(cfg)...)
+    router.GET("/healthz", healthHandler.LivenessHandler)
+    router.GET("/health", healthHandler.LivenessHandler)
+    router.GET("/readyz", healthHandler.ReadinessHandler)
     
     // All required endpoints
-    router.POST("/strings", handlers.PostStringHandler)
-    router.GET("/strings/:string_value", handlers.GetStringHandler)
-    router.GET("/strings", handlers.GetAllStringsHandler)
-    router.GET("/strings/filter-by-natural-language", handlers.NaturalLanguageFilterHandler)
-    router.DELETE("/strings/:string_value", handlers.DeleteStringHandler)
+    stringHandler := handlers.NewStringHandler(repo, handlers.Settings{
+        SecretPolicy: secretPolicy,
+        IDHash:       cfg.Analysis.PrimaryIDHash,
+        BatchWorkers: cfg.Batch.Workers,
+        MaxBatchSize: cfg.Batch.MaxItems,
+    })
+    maxBody := int64(cfg.Server.MaxBodyBytes)
+    limitBody := handlers.LimitBody(maxBody)
+    idempotency := handlers.NewIdempotency(repo, cfg.Server.IdempotencyTTL, maxBody)
+    router.POST("/strings", limitBody, idempotency.Middleware, stringHandler.PostStringHandler)
+    router.POST("/strings/batch", limitBody, idempotency.Middleware, stringHandler.PostBatchHandler)
+    router.POST("/strings/import", stringHandler.ImportHandler)
+    router.GET("/strings/export", stringHandler.ExportHandler)
+    router.GET("/strings/:string_value", stringHandler.GetStringHandler)
+    router.GET("/strings/by-hash/:algo/:digest", stringHandler.GetStringByHashHandler)
+    router.GET("/strings/id/:id", stringHandler.GetStringByIDHandler)
+    router.GET("/strings", stringHandler.GetAllStringsHandler)
+    router.GET("/strings/filter-by-natural-language", stringHandler.NaturalLanguageFilterHandler)
+    router.PUT("/strings/:string_value", limitBody, idempotency.Middleware, stringHandler.PutStringHandler)
+    router.DELETE("/strings/:string_value", stringHandler.DeleteStringHandler)
+    router.DELETE("/strings/id/:id", stringHandler.DeleteStringByIDHandler)
+    router.GET("/analyzers", handlers.ListAnalyzersHandler)
+    
+    server := &http.Server{
+        Addr:              ":" + cfg.Port,
+        Handler:           router,
+        ReadTimeout:       cfg.Server.ReadTimeout,
+        ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+        WriteTimeout:      cfg.Server.WriteTimeout,
+        IdleTimeout:       cfg.Server.IdleTimeout,
+    }
+    
+    ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+    defer stop()
     
-    port := os.Getenv("PORT")
-    if port == "" {
-        port = "8080"
+    serveErr := make(chan error, 1)
+    go func() {
+        log.Printf("Listening on %s", server.Addr)
+        serveErr <- server.ListenAndServe()
+    }()
+    
+    select {
+    case err := <-serveErr:
+        // The listener failed before any shutdown was requested
+        return err
+    case <-ctx.Done():
+    }
+    // A second signal kills the process immediately instead of waiting
+    stop()
+    
+    log.Printf("Shutting down, waiting up to %s for in-flight requests", cfg.Server.ShutdownTimeout)
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+    defer cancel()
+    if err := server.Shutdown(shutdownCtx); err != nil {
+        // Deadline hit: drop the remaining connections but still close storage
+        log.Println("Graceful shutdown timed out:", err)
+        server.Close()
+    }
+    if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+        return err
     }
     
-    router.Run(":" + port)
+    log.Println("Server stopped")
+    return nil
+}
+
+// newRepository opens the storage backend selected by STORAGE_BACKEND
+func newRepository(cfg config.Config) (storage.StringRepository, error) {
+    if cfg.StorageBackend == "memory" {
+        log.Println("Using in-memory storage; data will not survive a restart")
+        return storage.NewMemoryStorage(), nil
+    }
+    if err := database.Init(cfg.Database); err != nil {
+        return nil, err
+    }
+    return database.NewSQLiteRepository(database.DB), nil
+}
+
+// readinessChecks lists the dependencies /readyz probes for the configured backend
+func readinessChecks(cfg config.Config) []handlers.HealthCheck {
+    if cfg.StorageBackend == "memory" {
+        return nil
+    }
+    return []handlers.HealthCheck{
+        {Name: "database", Check: database.DB.PingContext},
+        {Name: "migrations", Check: func(ctx context.Context) error {
+            pending, err := database.CheckSchema(database.DB)
+            if err != nil {
+                return err
+            }
+            if pending {
+                return errors.New("schema migrations are pending")
+            }
+            return nil
+        }},
+    }
 }
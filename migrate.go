@@ -0,0 +1,91 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "text/tabwriter"
+    "github.com/holladworld/string-analyzer/config"
+    "github.com/holladworld/string-analyzer/database"
+)
+
+// runMigrate implements "migrate [up | down [STEPS] | status]". Unlike the
+// other commands it opens the database without migrating it first, so the
+// status can be inspected and migrations reverted.
+func runMigrate(args []string) error {
+    flags := newFlagSet("migrate", "[up | down [STEPS] | status]")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    action := "up"
+    if flags.NArg() > 0 {
+        action = flags.Arg(0)
+    }
+    steps := 1
+    switch {
+    case flags.NArg() > 2, flags.NArg() == 2 && action != "down":
+        flags.Usage()
+        return errors.New("too many arguments")
+    case action != "up" && action != "down" && action != "status":
+        flags.Usage()
+        return fmt.Errorf("unknown migrate action '%s' (allowed: up, down, status)", action)
+    case flags.NArg() == 2:
+        n, err := strconv.Atoi(flags.Arg(1))
+        if err != nil || n < 1 {
+            return fmt.Errorf("invalid STEPS '%s' (must be a positive integer)", flags.Arg(1))
+        }
+        steps = n
+    }
+    
+    cfg, err := config.Load()
+    if err != nil {
+        return err
+    }
+    if cfg.StorageBackend == "memory" {
+        return errors.New("migrate needs the sqlite backend, but STORAGE_BACKEND is memory")
+    }
+    if err := database.Open(cfg.Database); err != nil {
+        return err
+    }
+    defer database.DB.Close()
+    
+    switch action {
+    case "down":
+        return database.MigrateDown(database.DB, steps)
+    case "status":
+        return writeMigrationStatus()
+    }
+    if err := database.Migrate(database.DB); err != nil {
+        return err
+    }
+    version, err := database.SchemaVersion(database.DB)
+    if err != nil {
+        return err
+    }
+    log.Printf("Schema is at version %d", version)
+    return nil
+}
+
+// writeMigrationStatus lists every migration this build knows about
+func writeMigrationStatus() error {
+    statuses, err := database.MigrationStatuses(database.DB)
+    if err != nil {
+        return err
+    }
+    table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+    for _, status := range statuses {
+        state := "pending"
+        if status.Applied {
+            state = "applied"
+        }
+        fmt.Fprintf(table, "%04d\t%s\t%s\n", status.Version, status.Name, state)
+    }
+    if err := table.Flush(); err != nil {
+        return err
+    }
+    // A newer build may have applied migrations this one does not list
+    _, err = database.CheckSchema(database.DB)
+    return err
+}
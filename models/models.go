@@ -1,13 +1,74 @@
 package models
 
+// Finding locates a detected secret or piece of personal data by rune
+// offsets [Start, End)
+type Finding struct {
+    Type  string `json:"type"`
+    Start int    `json:"start"`
+    End   int    `json:"end"`
+    // Redacted marks a secret removed before storing; the offsets then
+    // refer to the submitted input rather than the stored value
+    Redacted bool `json:"redacted,omitempty"`
+}
+
+// TokenCount is a token and how often it occurs
+type TokenCount struct {
+    Token string `json:"token"`
+    Count int    `json:"count"`
+}
+
 type AnalysisResult struct {
-    ID                    string         `json:"id"`
-    Value                 string         `json:"value"`
-    Length                int            `json:"length"`
-    IsPalindrome          bool           `json:"is_palindrome"`
-    UniqueCharacters      int            `json:"unique_characters"`
-    WordCount             int            `json:"word_count"`
-    SHA256Hash            string         `json:"sha256_hash"`
-    CharacterFrequencyMap map[string]int `json:"character_frequency_map"` // Changed to string keys
-    CreatedAt             string         `json:"created_at"`
+    ID                      string         `json:"id"`
+    Value                   string         `json:"value"`
+    Length                  int            `json:"length"`         // Unicode code points
+    ByteLength              int            `json:"byte_length"`    // UTF-8 bytes
+    RuneCount               int            `json:"rune_count"`     // Unicode code points
+    GraphemeCount           int            `json:"grapheme_count"` // user-perceived characters
+    IsPalindrome            bool           `json:"is_palindrome"`
+    PalindromeMode          string         `json:"palindrome_mode"` // normalization used for IsPalindrome
+    LongestPalindrome       string         `json:"longest_palindrome"`
+    LongestPalindromeStart  int            `json:"longest_palindrome_start"`  // rune offset
+    LongestPalindromeEnd    int            `json:"longest_palindrome_end"`    // rune offset, exclusive
+    LongestPalindromeLength int            `json:"longest_palindrome_length"` // in runes
+    DistinctPalindromes     int            `json:"distinct_palindromes"`      // distinct palindromic substrings
+    PalindromicWords        []string       `json:"palindromic_words"`
+    UniqueCharacters        int            `json:"unique_characters"`
+    WordCount               int            `json:"word_count"`
+    SHA256Hash              string         `json:"sha256_hash"`
+    CharacterFrequencyMap   map[string]int `json:"character_frequency_map"` // Changed to string keys
+    WordFrequencyMap        map[string]int `json:"word_frequency_map"`
+    WordBigrams             map[string]int `json:"word_bigrams"`       // keyed "first second"
+    CharacterBigrams        map[string]int `json:"character_bigrams"`
+    CharacterTrigrams       map[string]int `json:"character_trigrams"`
+    TopWords                []TokenCount   `json:"top_words"`
+    MostCommonWord          string         `json:"most_common_word"`
+    HapaxLegomena           int            `json:"hapax_legomena"` // words occurring exactly once
+    SentenceCount           int            `json:"sentence_count"`
+    SyllableCount           int            `json:"syllable_count"`
+    AverageWordLength       float64        `json:"average_word_length"`
+    FleschReadingEase       float64        `json:"flesch_reading_ease"`
+    FleschKincaidGrade      float64        `json:"flesch_kincaid_grade"`
+    LexicalDiversity        float64        `json:"lexical_diversity"` // type/token ratio
+    ShannonEntropy          float64        `json:"shannon_entropy"` // bits per character
+    NormalizedEntropy       float64        `json:"normalized_entropy"`
+    CompressionRatio        float64        `json:"compression_ratio"` // DEFLATE size over UTF-8 size
+    ContainsSecret          bool            `json:"contains_secret"`
+    SecretFindings          []Finding       `json:"secret_findings"`
+    PIIFindings             []Finding       `json:"pii_findings"`
+    PIITypes                []string        `json:"pii_types"`
+    RedactedValue           string          `json:"redacted_value"` // Value with PII replaced
+    CategoryCounts          map[string]int  `json:"category_counts"` // letter, digit, punctuation, emoji, ...
+    ScriptCounts            map[string]int  `json:"script_counts"`
+    EmojiCount              int             `json:"emoji_count"`
+    MixedScript             bool            `json:"mixed_script"` // a word mixes scripts, e.g. a homoglyph
+    Language                string          `json:"language"` // ISO 639-1 code, empty when undetermined
+    LanguageConfidence      float64         `json:"language_confidence"`
+    // Hashes maps algorithm names onto hex digests; SHA-256 is always present
+    Hashes                  map[string]string `json:"hashes"`
+    // AnalyzerVersions maps each analyzer that ran to its version
+    AnalyzerVersions map[string]string `json:"analyzer_versions"`
+    // CustomProperties holds the output of analyzers registered outside the
+    // built-in set, keyed by analyzer name
+    CustomProperties map[string]interface{} `json:"custom_properties,omitempty"`
+    CreatedAt        string                 `json:"created_at"`
 }
@@ -1,51 +1,144 @@
 package services
 
 import (
-    "crypto/sha256"
-    "encoding/hex"
     "strings"
-    "time"
+    "unicode/utf8"
     "github.com/holladworld/string-analyzer/models"
+    "github.com/rivo/uniseg"
 )
 
+// Options tunes a single analysis. The zero value gives the defaults.
+type Options struct {
+    // PalindromeMode selects the normalization for the palindrome check
+    PalindromeMode PalindromeMode
+    // Hashes names extra digests to compute besides SHA-256, see HashAlgorithms
+    Hashes []string
+    // IDHash names the digest used as the ID; empty means DefaultIDHash
+    IDHash string
+}
+
+// AnalyzeString analyzes input with the default options. With those only a
+// bug can make the required hash analyzer fail, so it panics instead of
+// returning an error.
 func AnalyzeString(input string) models.AnalysisResult {
-    result := models.AnalysisResult{
-        Value: input,
-        CharacterFrequencyMap: make(map[string]int),
-        CreatedAt: time.Now().UTC().Format(time.RFC3339),
-    }
-    
-    // 1. Basic length
-    result.Length = len(input)
-    
-    // 2. Word count (split by spaces)
-    words := strings.Fields(input)
-    result.WordCount = len(words)
-    
-    // 3. Palindrome check (case insensitive)
-    cleanString := strings.ToLower(strings.ReplaceAll(input, " ", ""))
-    isPalindrome := true
-    for i := 0; i < len(cleanString)/2; i++ {
-        if cleanString[i] != cleanString[len(cleanString)-1-i] {
-            isPalindrome = false
-            break
-        }
+    result, err := AnalyzeStringWithOptions(input, Options{})
+    if err != nil {
+        panic(err)
     }
-    result.IsPalindrome = isPalindrome
-    
-    // 4. Character frequency and unique count (FIXED)
-    uniqueChars := make(map[rune]bool)
+    return result
+}
+
+// AnalyzeStringWithOptions runs every enabled analyzer in DefaultRegistry
+func AnalyzeStringWithOptions(input string, opts Options) (models.AnalysisResult, error) {
+    return DefaultRegistry.Analyze(input, opts)
+}
+
+// newDefaultRegistry registers the built-in analyzers. hash is required
+// because it provides the ID.
+func newDefaultRegistry() *Registry {
+    registry := NewRegistry()
+    registry.registerRequired(hashAnalyzer{})
+    registry.Register(lengthAnalyzer{})
+    registry.Register(wordsAnalyzer{})
+    registry.Register(palindromeAnalyzer{})
+    registry.Register(frequencyAnalyzer{})
+    registry.Register(ngramAnalyzer{})
+    registry.Register(readabilityAnalyzer{})
+    registry.Register(entropyAnalyzer{})
+    registry.Register(secretsAnalyzer{})
+    registry.Register(piiAnalyzer{})
+    registry.Register(scriptsAnalyzer{})
+    registry.Register(languageAnalyzer{})
+    return registry
+}
+
+// lengthAnalyzer counts bytes, code points and user-perceived characters.
+// Length is the code point count, matching UniqueCharacters and the frequency map.
+type lengthAnalyzer struct{}
+
+func (lengthAnalyzer) Name() string    { return "length" }
+func (lengthAnalyzer) Version() string { return "1" }
+
+func (lengthAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.ByteLength = len(input)
+    result.RuneCount = utf8.RuneCountInString(input)
+    result.GraphemeCount = uniseg.GraphemeClusterCount(input)
+    result.Length = result.RuneCount
+    return nil
+}
+
+// wordsAnalyzer counts whitespace-separated words
+type wordsAnalyzer struct{}
+
+func (wordsAnalyzer) Name() string    { return "words" }
+func (wordsAnalyzer) Version() string { return "1" }
+
+func (wordsAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.WordCount = len(strings.Fields(input))
+    return nil
+}
+
+// palindromeAnalyzer checks the whole string, normalized per the requested
+// mode, and computes the substring metrics
+type palindromeAnalyzer struct{}
+
+func (palindromeAnalyzer) Name() string    { return "palindrome" }
+func (palindromeAnalyzer) Version() string { return "1" }
+
+func (palindromeAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.IsPalindrome = IsPalindrome(input, opts.PalindromeMode)
+    result.PalindromeMode = string(opts.PalindromeMode)
+
+    palindromes := AnalyzePalindromes(input)
+    result.LongestPalindrome = palindromes.LongestPalindrome
+    result.LongestPalindromeStart = palindromes.LongestPalindromeStart
+    result.LongestPalindromeEnd = palindromes.LongestPalindromeEnd
+    result.LongestPalindromeLength = palindromes.LongestPalindromeEnd - palindromes.LongestPalindromeStart
+    result.DistinctPalindromes = palindromes.DistinctPalindromes
+    result.PalindromicWords = palindromes.PalindromicWords
+    return nil
+}
+
+// frequencyAnalyzer counts each code point and the number of distinct ones
+type frequencyAnalyzer struct{}
+
+func (frequencyAnalyzer) Name() string    { return "frequency" }
+func (frequencyAnalyzer) Version() string { return "1" }
+
+func (frequencyAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.CharacterFrequencyMap = make(map[string]int)
     for _, char := range input {
-        charStr := string(char)
-        result.CharacterFrequencyMap[charStr]++
-        uniqueChars[char] = true
+        result.CharacterFrequencyMap[string(char)]++
+    }
+    result.UniqueCharacters = len(result.CharacterFrequencyMap)
+    return nil
+}
+
+// hashAnalyzer computes the SHA-256 digest, any extra digests requested and
+// the ID, which is the SHA-256 digest unless opts.IDHash picks another
+type hashAnalyzer struct{}
+
+func (hashAnalyzer) Name() string    { return "hash" }
+func (hashAnalyzer) Version() string { return "1" }
+
+func (hashAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    idHash := opts.IDHash
+    if idHash == "" {
+        idHash = DefaultIDHash
     }
-    result.UniqueCharacters = len(uniqueChars)
-    
-    // 5. SHA256 hash
-    hash := sha256.Sum256([]byte(input))
-    result.SHA256Hash = hex.EncodeToString(hash[:])
-    result.ID = result.SHA256Hash
     
-    return result
+    result.Hashes = make(map[string]string)
+    for _, algorithm := range append([]string{"sha256", idHash}, opts.Hashes...) {
+        if _, done := result.Hashes[algorithm]; done {
+            continue
+        }
+        digest, err := Hash(algorithm, input)
+        if err != nil {
+            return err
+        }
+        result.Hashes[algorithm] = digest
+    }
+    result.SHA256Hash = result.Hashes["sha256"]
+    result.ID = result.Hashes[idHash]
+    return nil
 }
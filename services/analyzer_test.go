@@ -63,3 +63,33 @@ func TestCharacterFrequency(t *testing.T) {
         }
     }
 }
+
+// TestUnicodeLengths tests byte, rune and grapheme counts on multi-byte input
+func TestUnicodeLengths(t *testing.T) {
+    // "e" followed by a combining acute accent, then a precomposed "é"
+    result := AnalyzeString("éé")
+    
+    if result.ByteLength != 5 {
+        t.Errorf("Expected 5 bytes, got %d", result.ByteLength)
+    }
+    if result.RuneCount != 3 || result.Length != 3 {
+        t.Errorf("Expected 3 runes, got rune_count %d, length %d", result.RuneCount, result.Length)
+    }
+    if result.GraphemeCount != 2 {
+        t.Errorf("Expected 2 grapheme clusters, got %d", result.GraphemeCount)
+    }
+}
+
+// TestUnicodePalindrome tests palindromes that need case folding and grapheme comparison
+func TestUnicodePalindrome(t *testing.T) {
+    palindromes := []string{"été", "Été", "été", "Ésé", "ÅbbÅ", "👍🏽x👍🏽", "e\u0301t\u00e9"}
+    for _, input := range palindromes {
+        if !AnalyzeString(input).IsPalindrome {
+            t.Errorf("%q should be detected as palindrome", input)
+        }
+    }
+    
+    if AnalyzeString("éte").IsPalindrome {
+        t.Error("'éte' should not be detected as palindrome")
+    }
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+    "bytes"
+    "compress/flate"
+    "math"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// entropyAnalyzer measures how random a string looks: Shannon entropy over
+// code points and how well it compresses with DEFLATE
+type entropyAnalyzer struct{}
+
+func (entropyAnalyzer) Name() string    { return "entropy" }
+func (entropyAnalyzer) Version() string { return "1" }
+
+func (entropyAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.ShannonEntropy, result.NormalizedEntropy = ShannonEntropy(input)
+    ratio, err := CompressionRatio(input)
+    if err != nil {
+        return err
+    }
+    result.CompressionRatio = ratio
+    return nil
+}
+
+// ShannonEntropy returns the entropy of the code point distribution in bits
+// per character, and the same value divided by its maximum for the number of
+// distinct characters, which is 0 when there are fewer than two.
+func ShannonEntropy(input string) (float64, float64) {
+    counts := make(map[rune]int)
+    total := 0
+    for _, r := range input {
+        counts[r]++
+        total++
+    }
+    if len(counts) < 2 {
+        return 0, 0
+    }
+    
+    entropy := 0.0
+    for _, count := range counts {
+        p := float64(count) / float64(total)
+        entropy -= p * math.Log2(p)
+    }
+    return entropy, entropy / math.Log2(float64(len(counts)))
+}
+
+// CompressionRatio returns the raw DEFLATE size over the UTF-8 size. Short or
+// random strings come out at or above 1; repetitive text well below it.
+func CompressionRatio(input string) (float64, error) {
+    if input == "" {
+        return 0, nil
+    }
+    var buf bytes.Buffer
+    writer, err := flate.NewWriter(&buf, flate.BestCompression)
+    if err != nil {
+        return 0, err
+    }
+    if _, err := writer.Write([]byte(input)); err != nil {
+        return 0, err
+    }
+    if err := writer.Close(); err != nil {
+        return 0, err
+    }
+    return float64(buf.Len()) / float64(len(input)), nil
+}
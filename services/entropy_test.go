@@ -0,0 +1,45 @@
+package services
+
+import (
+    "math"
+    "strings"
+    "testing"
+)
+
+// TestShannonEntropy tests entropy on uniform and degenerate inputs
+func TestShannonEntropy(t *testing.T) {
+    cases := []struct {
+        input      string
+        entropy    float64
+        normalized float64
+    }{
+        {"", 0, 0},
+        {"aaaa", 0, 0},
+        {"abab", 1, 1},
+        {"abcd", 2, 1},
+        {"aab", 0.9183, 0.9183},
+    }
+    for _, tc := range cases {
+        entropy, normalized := ShannonEntropy(tc.input)
+        if math.Abs(entropy-tc.entropy) > 1e-4 || math.Abs(normalized-tc.normalized) > 1e-4 {
+            t.Errorf("ShannonEntropy(%q) = %v, %v, want %v, %v", tc.input, entropy, normalized, tc.entropy, tc.normalized)
+        }
+    }
+}
+
+// TestCompressionRatio tests that repetitive text compresses and random text does not
+func TestCompressionRatio(t *testing.T) {
+    repetitive, err := CompressionRatio(strings.Repeat("abc", 200))
+    if err != nil || repetitive >= 0.1 {
+        t.Errorf("Expected repetitive text to compress well, got %v (%v)", repetitive, err)
+    }
+    token, err := CompressionRatio("x7Qp2LmZ9vTbK4sWnR8e")
+    if err != nil || token < 1 {
+        t.Errorf("Expected a random token not to compress, got %v (%v)", token, err)
+    }
+    
+    result := AnalyzeString("x7Qp2LmZ9vTbK4sWnR8e")
+    if result.ShannonEntropy < 4 || result.NormalizedEntropy != 1 || result.CompressionRatio != token {
+        t.Errorf("Unexpected entropy metrics %v, %v, %v", result.ShannonEntropy, result.NormalizedEntropy, result.CompressionRatio)
+    }
+}
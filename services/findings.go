@@ -0,0 +1,62 @@
+package services
+
+import (
+    "sort"
+    "strings"
+    "unicode/utf8"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// findingSet collects non-overlapping findings. Detectors add matches in
+// priority order, so a later, more generic rule never claims text an
+// earlier one already reported.
+type findingSet struct {
+    input    string
+    spans    [][2]int // byte offsets of accepted findings
+    findings []models.Finding
+}
+
+func newFindingSet(input string) *findingSet {
+    return &findingSet{input: input, findings: make([]models.Finding, 0)}
+}
+
+// add records input[start:end] as kind unless it overlaps an earlier finding
+func (s *findingSet) add(kind string, start, end int) bool {
+    for _, span := range s.spans {
+        if start < span[1] && span[0] < end {
+            return false
+        }
+    }
+    s.spans = append(s.spans, [2]int{start, end})
+    runeStart := utf8.RuneCountInString(s.input[:start])
+    s.findings = append(s.findings, models.Finding{
+        Type: kind,
+        Start: runeStart,
+        End: runeStart + utf8.RuneCountInString(s.input[start:end]),
+    })
+    return true
+}
+
+// sorted returns the findings ordered by position
+func (s *findingSet) sorted() []models.Finding {
+    sort.Slice(s.findings, func(i, j int) bool { return s.findings[i].Start < s.findings[j].Start })
+    return s.findings
+}
+
+// Redact replaces every finding with a [REDACTED:<type>] marker. Findings
+// must be ordered by position and must not overlap.
+func Redact(input string, findings []models.Finding) string {
+    runes := []rune(input)
+    var b strings.Builder
+    previous := 0
+    for _, finding := range findings {
+        if finding.Start < previous || finding.End > len(runes) {
+            continue
+        }
+        b.WriteString(string(runes[previous:finding.Start]))
+        b.WriteString("[REDACTED:" + finding.Type + "]")
+        previous = finding.End
+    }
+    b.WriteString(string(runes[previous:]))
+    return b.String()
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+    "crypto/md5"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+    "hash/crc32"
+    "hash/fnv"
+    "sort"
+    "strings"
+    "github.com/cespare/xxhash/v2"
+    "golang.org/x/crypto/blake2b"
+)
+
+// hashAlgorithms are the digests clients can ask for with ?hashes=. Every
+// digest is rendered as lower-case hex. blake2b is BLAKE2b-256, xxhash is
+// XXH64 and fnv is FNV-1a 64.
+var hashAlgorithms = map[string]func(data []byte) []byte{
+    "md5":     func(b []byte) []byte { sum := md5.Sum(b); return sum[:] },
+    "sha1":    func(b []byte) []byte { sum := sha1.Sum(b); return sum[:] },
+    "sha256":  func(b []byte) []byte { sum := sha256.Sum256(b); return sum[:] },
+    "sha512":  func(b []byte) []byte { sum := sha512.Sum512(b); return sum[:] },
+    "blake2b": func(b []byte) []byte { sum := blake2b.Sum256(b); return sum[:] },
+    "crc32":   func(b []byte) []byte { return binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE(b)) },
+    "xxhash":  func(b []byte) []byte { return binary.BigEndian.AppendUint64(nil, xxhash.Sum64(b)) },
+    "fnv": func(b []byte) []byte {
+        h := fnv.New64a()
+        h.Write(b)
+        return h.Sum(nil)
+    },
+}
+
+// DefaultIDHash is the digest used as the ID unless configured otherwise
+const DefaultIDHash = "sha256"
+
+// HashAlgorithms lists every supported digest name
+func HashAlgorithms() []string {
+    names := make([]string, 0, len(hashAlgorithms))
+    for name := range hashAlgorithms {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// ParseHashes validates a comma-separated list of digest names such as
+// "sha1,blake2b", dropping blanks and repeats
+func ParseHashes(spec string) ([]string, error) {
+    var names []string
+    seen := make(map[string]bool)
+    for _, name := range strings.Split(spec, ",") {
+        name = strings.ToLower(strings.TrimSpace(name))
+        if name == "" || seen[name] {
+            continue
+        }
+        if _, ok := hashAlgorithms[name]; !ok {
+            return nil, fmt.Errorf("unknown hash '%s' (allowed: %s)", name, strings.Join(HashAlgorithms(), ", "))
+        }
+        seen[name] = true
+        names = append(names, name)
+    }
+    return names, nil
+}
+
+// Hash returns the hex digest of input with the named algorithm
+func Hash(algorithm, input string) (string, error) {
+    sum, ok := hashAlgorithms[algorithm]
+    if !ok {
+        return "", fmt.Errorf("unknown hash '%s'", algorithm)
+    }
+    return hex.EncodeToString(sum([]byte(input))), nil
+}
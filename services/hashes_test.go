@@ -0,0 +1,53 @@
+package services
+
+import (
+    "testing"
+)
+
+// TestHash tests every algorithm against a known digest of "hello"
+func TestHash(t *testing.T) {
+    expected := map[string]string{
+        "md5":     "5d41402abc4b2a76b9719d911017c592",
+        "sha1":    "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+        "sha256":  "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+        "sha512":  "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043",
+        "blake2b": "324dcf027dd4a30a932c441f365a25e86b173defa4b8e58948253471b81b72cf",
+        "crc32":   "3610a686",
+        "xxhash":  "26c7827d889f6da3",
+        "fnv":     "a430d84680aabd0b",
+    }
+    for algorithm, digest := range expected {
+        got, err := Hash(algorithm, "hello")
+        if err != nil || got != digest {
+            t.Errorf("Hash(%s) = %s, %v, want %s", algorithm, got, err, digest)
+        }
+    }
+    if len(HashAlgorithms()) != len(expected) {
+        t.Errorf("Expected %d algorithms, got %v", len(expected), HashAlgorithms())
+    }
+}
+
+// TestParseHashes tests validation of the ?hashes= list
+func TestParseHashes(t *testing.T) {
+    names, err := ParseHashes(" SHA1,blake2b,,sha1")
+    if err != nil || len(names) != 2 || names[0] != "sha1" || names[1] != "blake2b" {
+        t.Errorf("ParseHashes = %v, %v", names, err)
+    }
+    if _, err := ParseHashes("sha3"); err == nil {
+        t.Error("Expected an error for an unknown hash")
+    }
+}
+
+// TestIDHash tests choosing the digest used as the ID
+func TestIDHash(t *testing.T) {
+    result, err := AnalyzeStringWithOptions("hello", Options{Hashes: []string{"crc32"}, IDHash: "blake2b"})
+    if err != nil {
+        t.Fatalf("AnalyzeStringWithOptions failed: %v", err)
+    }
+    if result.ID != result.Hashes["blake2b"] || result.SHA256Hash != result.Hashes["sha256"] || result.Hashes["crc32"] != "3610a686" {
+        t.Errorf("Unexpected ID %s and hashes %v", result.ID, result.Hashes)
+    }
+    if len(result.Hashes) != 3 {
+        t.Errorf("Expected sha256, blake2b and crc32, got %v", result.Hashes)
+    }
+}
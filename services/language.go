@@ -0,0 +1,235 @@
+package services
+
+import (
+    "embed"
+    "math"
+    "path"
+    "sort"
+    "strings"
+    "sync"
+    "unicode"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// Sample texts, one per language, from which the trigram profiles are built.
+// Add a language by adding langdata/<ISO 639-1 code>.txt with a few
+// paragraphs of ordinary prose and an entry in languageNames.
+//
+//go:embed langdata/*.txt
+var languageSamples embed.FS
+
+// languageNames maps the supported ISO 639-1 codes onto English names
+var languageNames = map[string]string{
+    "en": "english", "fr": "french", "de": "german", "es": "spanish",
+    "it": "italian", "pt": "portuguese", "nl": "dutch", "sv": "swedish",
+    "pl": "polish", "tr": "turkish", "ru": "russian", "uk": "ukrainian",
+    "zh": "chinese", "ja": "japanese", "ko": "korean", "ar": "arabic",
+    "he": "hebrew", "el": "greek", "th": "thai", "hi": "hindi",
+}
+
+// scriptLanguages decides the language from the script alone, for scripts
+// used by a single supported language. Han and kana are handled separately.
+var scriptLanguages = map[string]string{
+    "Hangul": "ko", "Arabic": "ar", "Hebrew": "he", "Greek": "el",
+    "Thai": "th", "Devanagari": "hi",
+}
+
+// languageSampleLimit caps how many letters of a long input are scored
+const languageSampleLimit = 10000
+
+// minLanguageLetters is the fewest letters worth guessing a language from
+const minLanguageLetters = 3
+
+// minLanguageConfidence is the lowest confidence reported as a detection;
+// below it the language is left undetermined
+const minLanguageConfidence = 0.5
+
+// Languages lists the supported ISO 639-1 codes
+func Languages() []string {
+    codes := make([]string, 0, len(languageNames))
+    for code := range languageNames {
+        codes = append(codes, code)
+    }
+    sort.Strings(codes)
+    return codes
+}
+
+// LanguageName returns the English name of a supported language code
+func LanguageName(code string) (string, bool) {
+    name, ok := languageNames[code]
+    return name, ok
+}
+
+// languageProfile holds trigram counts for one language
+type languageProfile struct {
+    code     string
+    script   string
+    trigrams map[string]int
+    total    int
+}
+
+var (
+    profilesOnce sync.Once
+    profiles     []*languageProfile
+)
+
+// loadProfiles builds the profiles from the embedded samples on first use
+func loadProfiles() []*languageProfile {
+    profilesOnce.Do(func() {
+        entries, err := languageSamples.ReadDir("langdata")
+        if err != nil {
+            panic(err)
+        }
+        for _, entry := range entries {
+            text, err := languageSamples.ReadFile(path.Join("langdata", entry.Name()))
+            if err != nil {
+                panic(err)
+            }
+            profile := &languageProfile{
+                code: strings.TrimSuffix(entry.Name(), ".txt"),
+                trigrams: make(map[string]int),
+            }
+            profile.script, _ = dominantScript(string(text))
+            for _, trigram := range wordTrigrams(string(text), -1) {
+                profile.trigrams[trigram]++
+                profile.total++
+            }
+            profiles = append(profiles, profile)
+        }
+    })
+    return profiles
+}
+
+// wordTrigrams returns the rune trigrams of every word padded with spaces,
+// reading at most limit letters (-1 for all)
+func wordTrigrams(input string, limit int) []string {
+    var trigrams []string
+    letters := 0
+    for _, word := range Words(input) {
+        if strings.IndexFunc(word, unicode.IsLetter) < 0 {
+            continue
+        }
+        runes := []rune(" " + word + " ")
+        for i := 0; i+3 <= len(runes); i++ {
+            trigrams = append(trigrams, string(runes[i:i+3]))
+        }
+        letters += len(runes) - 2
+        if limit >= 0 && letters >= limit {
+            break
+        }
+    }
+    return trigrams
+}
+
+// dominantScript returns the script most letters belong to and that share
+func dominantScript(input string) (string, float64) {
+    counts := make(map[string]int)
+    letters := 0
+    for _, r := range input {
+        if !unicode.IsLetter(r) {
+            continue
+        }
+        letters++
+        if script := ScriptOf(r); script != "" {
+            counts[script]++
+        }
+        if letters >= languageSampleLimit {
+            break
+        }
+    }
+    best := ""
+    for script, count := range counts {
+        if best == "" || count > counts[best] || (count == counts[best] && script < best) {
+            best = script
+        }
+    }
+    if letters == 0 {
+        return "", 0
+    }
+    return best, float64(counts[best]) / float64(letters)
+}
+
+// DetectLanguage guesses the ISO 639-1 code of input with a confidence
+// between 0 and 1. Scripts used by a single language decide directly;
+// Latin and Cyrillic text is scored against the trigram profiles with
+// naive Bayes. It returns "" when there is too little text to tell or no
+// language is a clear winner.
+func DetectLanguage(input string) (string, float64) {
+    script, share := dominantScript(input)
+    letters := 0
+    for _, r := range input {
+        if unicode.IsLetter(r) {
+            letters++
+        }
+    }
+    if script == "" || letters < minLanguageLetters {
+        return "", 0
+    }
+    
+    if script == "Han" || script == "Hiragana" || script == "Katakana" {
+        // Japanese mixes kanji with kana; Chinese uses Han alone
+        cjk, kana := 0, 0
+        for _, r := range input {
+            switch {
+            case unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r):
+                kana++
+                cjk++
+            case unicode.Is(unicode.Han, r):
+                cjk++
+            }
+        }
+        share = math.Min(1, float64(cjk)/float64(letters))
+        if kana > 0 {
+            return "ja", round2(share)
+        }
+        return "zh", round2(share)
+    }
+    if code, ok := scriptLanguages[script]; ok {
+        return code, round2(share)
+    }
+    
+    var candidates []*languageProfile
+    for _, profile := range loadProfiles() {
+        if profile.script == script {
+            candidates = append(candidates, profile)
+        }
+    }
+    trigrams := wordTrigrams(input, languageSampleLimit)
+    if len(candidates) == 0 || len(trigrams) == 0 {
+        return "", 0
+    }
+    
+    scores := make([]float64, len(candidates))
+    best := 0
+    for i, profile := range candidates {
+        denominator := math.Log(float64(profile.total + len(profile.trigrams)))
+        for _, trigram := range trigrams {
+            scores[i] += math.Log(float64(profile.trigrams[trigram]+1)) - denominator
+        }
+        if scores[i] > scores[best] {
+            best = i
+        }
+    }
+    
+    // The posterior of the best language, assuming equal priors
+    sum := 0.0
+    for _, score := range scores {
+        sum += math.Exp(score - scores[best])
+    }
+    confidence := share / sum
+    if confidence < minLanguageConfidence {
+        return "", 0
+    }
+    return candidates[best].code, round2(confidence)
+}
+
+// languageAnalyzer records the detected language and its confidence
+type languageAnalyzer struct{}
+
+func (languageAnalyzer) Name() string    { return "language" }
+func (languageAnalyzer) Version() string { return "1" }
+
+func (languageAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.Language, result.LanguageConfidence = DetectLanguage(input)
+    return nil
+}
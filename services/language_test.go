@@ -0,0 +1,47 @@
+package services
+
+import (
+    "testing"
+)
+
+// TestDetectLanguage tests trigram scoring and the script shortcuts
+func TestDetectLanguage(t *testing.T) {
+    cases := map[string]string{
+        "Hello, how are you today?": "en",
+        "Bonjour, comment allez-vous aujourd'hui ?": "fr",
+        "Guten Morgen, wie geht es dir?": "de",
+        "¿Dónde está la biblioteca?": "es",
+        "Dove si trova la stazione?": "it",
+        "Ik weet het niet, misschien morgen": "nl",
+        "Nie wiem, może jutro": "pl",
+        "Я не знаю, может быть завтра": "ru",
+        "東京タワーへようこそ": "ja",
+        "北京欢迎你": "zh",
+        "안녕하세요": "ko",
+        "مرحبا بالعالم": "ar",
+    }
+    for input, expected := range cases {
+        language, confidence := DetectLanguage(input)
+        if language != expected || confidence < 0.5 || confidence > 1 {
+            t.Errorf("DetectLanguage(%q) = %q, %v, want %q", input, language, confidence, expected)
+        }
+    }
+    
+    for _, input := range []string{"", "hi", "12345 !!!", "racecar"} {
+        if language, confidence := DetectLanguage(input); language != "" || confidence != 0 {
+            t.Errorf("DetectLanguage(%q) = %q, %v, want undetermined", input, language, confidence)
+        }
+    }
+}
+
+// TestLanguageProfiles tests that every sample has a name and a profile
+func TestLanguageProfiles(t *testing.T) {
+    for _, profile := range loadProfiles() {
+        if _, ok := LanguageName(profile.code); !ok {
+            t.Errorf("Sample %s.txt has no entry in languageNames", profile.code)
+        }
+        if profile.total < 500 {
+            t.Errorf("Sample %s.txt is too short (%d trigrams)", profile.code, profile.total)
+        }
+    }
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+    "sort"
+    "strings"
+    "unicode"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// DefaultTopK is how many of the most common words are reported
+const DefaultTopK = 10
+
+// ngramAnalyzer computes word frequencies, character and word n-grams, the
+// most common words and the hapax legomena (words seen exactly once)
+type ngramAnalyzer struct{}
+
+func (ngramAnalyzer) Name() string    { return "ngrams" }
+func (ngramAnalyzer) Version() string { return "1" }
+
+func (ngramAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    words := Words(input)
+    
+    result.WordFrequencyMap = make(map[string]int)
+    for _, word := range words {
+        result.WordFrequencyMap[word]++
+    }
+    result.WordBigrams = make(map[string]int)
+    for i := 1; i < len(words); i++ {
+        result.WordBigrams[words[i-1]+" "+words[i]]++
+    }
+    
+    runes := []rune(strings.ToLower(input))
+    result.CharacterBigrams = characterNgrams(runes, 2)
+    result.CharacterTrigrams = characterNgrams(runes, 3)
+    
+    result.TopWords = topTokens(result.WordFrequencyMap, DefaultTopK)
+    result.MostCommonWord = ""
+    if len(result.TopWords) > 0 {
+        result.MostCommonWord = result.TopWords[0].Token
+    }
+    result.HapaxLegomena = 0
+    for _, count := range result.WordFrequencyMap {
+        if count == 1 {
+            result.HapaxLegomena++
+        }
+    }
+    return nil
+}
+
+// Words splits input into lower-cased words. Punctuation separates words,
+// except apostrophes inside a word, so "don't" stays one token.
+func Words(input string) []string {
+    words := make([]string, 0)
+    fields := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+        return !isWordRune(r) && !isApostrophe(r)
+    })
+    for _, field := range fields {
+        word := strings.TrimFunc(field, isApostrophe)
+        if word != "" {
+            words = append(words, word)
+        }
+    }
+    return words
+}
+
+func isApostrophe(r rune) bool {
+    return r == '\'' || r == '’'
+}
+
+// characterNgrams counts every run of n consecutive runes
+func characterNgrams(runes []rune, n int) map[string]int {
+    counts := make(map[string]int)
+    for i := 0; i+n <= len(runes); i++ {
+        gram := runes[i : i+n]
+        // Runs of whitespace only tell us about formatting, not text
+        if strings.TrimFunc(string(gram), unicode.IsSpace) == "" {
+            continue
+        }
+        counts[string(gram)]++
+    }
+    return counts
+}
+
+// topTokens returns the k most frequent tokens, ties broken alphabetically
+func topTokens(counts map[string]int, k int) []models.TokenCount {
+    tokens := make([]models.TokenCount, 0, len(counts))
+    for token, count := range counts {
+        tokens = append(tokens, models.TokenCount{Token: token, Count: count})
+    }
+    sort.Slice(tokens, func(i, j int) bool {
+        if tokens[i].Count != tokens[j].Count {
+            return tokens[i].Count > tokens[j].Count
+        }
+        return tokens[i].Token < tokens[j].Token
+    })
+    if len(tokens) > k {
+        tokens = tokens[:k]
+    }
+    return tokens
+}
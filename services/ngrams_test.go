@@ -0,0 +1,50 @@
+package services
+
+import (
+    "testing"
+)
+
+// TestWords tests tokenization on punctuation and apostrophes
+func TestWords(t *testing.T) {
+    words := Words("Don't stop, the END... 'quoted'")
+    expected := []string{"don't", "stop", "the", "end", "quoted"}
+    if len(words) != len(expected) {
+        t.Fatalf("Words = %v, want %v", words, expected)
+    }
+    for i := range expected {
+        if words[i] != expected[i] {
+            t.Errorf("Words[%d] = %q, want %q", i, words[i], expected[i])
+        }
+    }
+}
+
+// TestWordStatistics tests word frequencies, n-grams, top words and hapax count
+func TestWordStatistics(t *testing.T) {
+    result := AnalyzeString("The cat and the hat. The end")
+    
+    if result.WordFrequencyMap["the"] != 3 || result.WordFrequencyMap["cat"] != 1 {
+        t.Errorf("Unexpected word frequencies %v", result.WordFrequencyMap)
+    }
+    if result.WordBigrams["the cat"] != 1 || result.WordBigrams["hat the"] != 1 {
+        t.Errorf("Unexpected word bigrams %v", result.WordBigrams)
+    }
+    if result.CharacterBigrams["th"] != 3 || result.CharacterTrigrams["the"] != 3 {
+        t.Errorf("Unexpected character n-grams: th=%d the=%d", result.CharacterBigrams["th"], result.CharacterTrigrams["the"])
+    }
+    if result.MostCommonWord != "the" {
+        t.Errorf("Expected most common word 'the', got %q", result.MostCommonWord)
+    }
+    // Ties are broken alphabetically after the count
+    if len(result.TopWords) != 5 || result.TopWords[0].Count != 3 || result.TopWords[1].Token != "and" {
+        t.Errorf("Unexpected top words %v", result.TopWords)
+    }
+    // and, cat, hat, end occur once
+    if result.HapaxLegomena != 4 {
+        t.Errorf("Expected 4 hapax legomena, got %d", result.HapaxLegomena)
+    }
+    
+    empty := AnalyzeString("")
+    if empty.MostCommonWord != "" || len(empty.TopWords) != 0 || empty.HapaxLegomena != 0 {
+        t.Errorf("Expected no word statistics for empty input, got %+v", empty)
+    }
+}
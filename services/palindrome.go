@@ -0,0 +1,117 @@
+package services
+
+import (
+    "fmt"
+    "strings"
+    "unicode"
+    "github.com/rivo/uniseg"
+    "golang.org/x/text/cases"
+    "golang.org/x/text/unicode/norm"
+)
+
+// PalindromeMode names how a string is normalized before the palindrome check
+type PalindromeMode string
+
+const (
+    // PalindromeStrict compares the string as is, apart from NFC normalization
+    PalindromeStrict PalindromeMode = "strict"
+    // PalindromeIgnoreWhitespace folds case and drops whitespace
+    PalindromeIgnoreWhitespace PalindromeMode = "ignore-whitespace"
+    // PalindromeAlphanumeric folds case and keeps only letters, digits and their accents
+    PalindromeAlphanumeric PalindromeMode = "alphanumeric-only"
+    // PalindromeAccentInsensitive is alphanumeric-only with accents stripped
+    PalindromeAccentInsensitive PalindromeMode = "accent-insensitive"
+)
+
+// DefaultPalindromeMode matches the behaviour from before modes existed
+const DefaultPalindromeMode = PalindromeIgnoreWhitespace
+
+var palindromeModes = []PalindromeMode{
+    PalindromeStrict,
+    PalindromeIgnoreWhitespace,
+    PalindromeAlphanumeric,
+    PalindromeAccentInsensitive,
+}
+
+// PalindromeModes lists every supported mode
+func PalindromeModes() []PalindromeMode {
+    return append([]PalindromeMode(nil), palindromeModes...)
+}
+
+// ParsePalindromeMode validates a mode name; empty selects the default
+func ParsePalindromeMode(name string) (PalindromeMode, error) {
+    if name == "" {
+        return DefaultPalindromeMode, nil
+    }
+    for _, mode := range palindromeModes {
+        if string(mode) == name {
+            return mode, nil
+        }
+    }
+    names := make([]string, len(palindromeModes))
+    for i, mode := range palindromeModes {
+        names[i] = string(mode)
+    }
+    return "", fmt.Errorf("unknown palindrome mode '%s' (allowed: %s)", name, strings.Join(names, ", "))
+}
+
+// Normalize applies the mode to s, producing the text the check compares
+func (mode PalindromeMode) Normalize(s string) string {
+    switch mode {
+    case PalindromeStrict:
+        return norm.NFC.String(s)
+    case PalindromeAlphanumeric:
+        return norm.NFC.String(keepAlphanumeric(cases.Fold().String(s)))
+    case PalindromeAccentInsensitive:
+        decomposed := norm.NFD.String(keepAlphanumeric(cases.Fold().String(s)))
+        return norm.NFC.String(strings.Map(func(r rune) rune {
+            if unicode.Is(unicode.Mn, r) {
+                return -1
+            }
+            return r
+        }, decomposed))
+    default:
+        return norm.NFC.String(strings.Map(func(r rune) rune {
+            if unicode.IsSpace(r) {
+                return -1
+            }
+            return r
+        }, cases.Fold().String(s)))
+    }
+}
+
+// keepAlphanumeric drops everything but letters, numbers and combining marks,
+// so accents stay attached to the letters they modify.
+func keepAlphanumeric(s string) string {
+    return strings.Map(func(r rune) rune {
+        if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.M, r) {
+            return r
+        }
+        return -1
+    }, s)
+}
+
+// IsPalindrome compares grapheme clusters of the normalized string, so a
+// combining accent stays attached to its base letter instead of being
+// reversed onto the wrong one.
+func IsPalindrome(s string, mode PalindromeMode) bool {
+    clusters := graphemes(mode.Normalize(s))
+    for i, j := 0, len(clusters)-1; i < j; i, j = i+1, j-1 {
+        if clusters[i] != clusters[j] {
+            return false
+        }
+    }
+    return true
+}
+
+// graphemes splits s into user-perceived characters
+func graphemes(s string) []string {
+    var clusters []string
+    state := -1
+    for len(s) > 0 {
+        var cluster string
+        cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+        clusters = append(clusters, cluster)
+    }
+    return clusters
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+    "strings"
+    "unicode"
+)
+
+// PalindromeMetrics describes the palindromic structure inside a string.
+// Offsets are rune indexes into the original value, end exclusive. Matching is
+// case-insensitive, and the longest palindrome has surrounding whitespace and
+// punctuation trimmed.
+type PalindromeMetrics struct {
+    LongestPalindrome      string
+    LongestPalindromeStart int
+    LongestPalindromeEnd   int
+    DistinctPalindromes    int
+    PalindromicWords       []string
+}
+
+// AnalyzePalindromes computes the palindrome metrics in linear time. Runes are
+// lower-cased one to one so that offsets still line up with the input.
+func AnalyzePalindromes(input string) PalindromeMetrics {
+    runes := []rune(input)
+    folded := make([]rune, len(runes))
+    for i, r := range runes {
+        folded[i] = unicode.ToLower(r)
+    }
+    
+    start, end := longestPalindrome(folded)
+    // " racecar " is longer than "racecar" but the padding is noise; trimming
+    // matching ends keeps the result a palindrome
+    for end-start > 1 && !isWordRune(folded[start]) && !isWordRune(folded[end-1]) {
+        start, end = start+1, end-1
+    }
+    return PalindromeMetrics{
+        LongestPalindrome:      string(runes[start:end]),
+        LongestPalindromeStart: start,
+        LongestPalindromeEnd:   end,
+        DistinctPalindromes:    countDistinctPalindromes(folded),
+        PalindromicWords:       palindromicWords(input),
+    }
+}
+
+// longestPalindrome finds the leftmost longest palindromic run with
+// Manacher's algorithm, O(n) time and space.
+func longestPalindrome(s []rune) (start, end int) {
+    n := len(s)
+    bestStart, bestLen := 0, 0
+    
+    // Odd lengths: odd[i] is the radius of the palindrome centred on i
+    odd := make([]int32, n)
+    for i, l, r := 0, 0, -1; i < n; i++ {
+        k := 1
+        if i <= r {
+            k = min(int(odd[l+r-i]), r-i+1)
+        }
+        for i-k >= 0 && i+k < n && s[i-k] == s[i+k] {
+            k++
+        }
+        odd[i] = int32(k)
+        if i+k-1 > r {
+            l, r = i-k+1, i+k-1
+        }
+        if 2*k-1 > bestLen {
+            bestStart, bestLen = i-k+1, 2*k-1
+        }
+    }
+    
+    // Even lengths: even[i] is the radius of the palindrome centred between i-1 and i
+    even := make([]int32, n)
+    for i, l, r := 0, 0, -1; i < n; i++ {
+        k := 0
+        if i <= r {
+            k = min(int(even[l+r-i+1]), r-i+1)
+        }
+        for i-k-1 >= 0 && i+k < n && s[i-k-1] == s[i+k] {
+            k++
+        }
+        even[i] = int32(k)
+        if i+k-1 > r {
+            l, r = i-k, i+k-1
+        }
+        if 2*k > bestLen || (2*k == bestLen && i-k < bestStart) {
+            bestStart, bestLen = i-k, 2*k
+        }
+    }
+    
+    return bestStart, bestStart + bestLen
+}
+
+// eertreeNode is a node of a palindromic tree: one distinct palindrome
+type eertreeNode struct {
+    length int32
+    // link is the longest proper palindromic suffix
+    link int32
+}
+
+// countDistinctPalindromes counts distinct non-empty palindromic substrings
+// with an eertree, which adds at most one node per rune, so O(n) overall.
+func countDistinctPalindromes(s []rune) int {
+    // Node 0 is the imaginary root of length -1, node 1 the empty palindrome
+    nodes := []eertreeNode{{length: -1, link: 0}, {length: 0, link: 0}}
+    edges := make(map[uint64]int32)
+    edgeKey := func(node int32, r rune) uint64 {
+        return uint64(node)<<32 | uint64(uint32(r))
+    }
+    // extends reports whether s[i] can wrap the palindrome at node
+    extends := func(node int32, i int) bool {
+        j := i - 1 - int(nodes[node].length)
+        return j >= 0 && s[j] == s[i]
+    }
+    
+    last := int32(1)
+    for i, r := range s {
+        cur := last
+        for !extends(cur, i) {
+            cur = nodes[cur].link
+        }
+        if next, ok := edges[edgeKey(cur, r)]; ok {
+            last = next
+            continue
+        }
+        
+        node := eertreeNode{length: nodes[cur].length + 2, link: 1}
+        if node.length > 1 {
+            suffix := nodes[cur].link
+            for !extends(suffix, i) {
+                suffix = nodes[suffix].link
+            }
+            node.link = edges[edgeKey(suffix, r)]
+        }
+        nodes = append(nodes, node)
+        last = int32(len(nodes) - 1)
+        edges[edgeKey(cur, r)] = last
+    }
+    
+    return len(nodes) - 2
+}
+
+// palindromicWords lists the distinct words of two or more letters that read
+// the same backwards, lower-cased, in order of first appearance.
+func palindromicWords(input string) []string {
+    words := make([]string, 0)
+    seen := make(map[string]bool)
+    fields := strings.FieldsFunc(input, func(r rune) bool { return !isWordRune(r) })
+    for _, field := range fields {
+        word := strings.ToLower(field)
+        if seen[word] || len(graphemes(word)) < 2 {
+            continue
+        }
+        seen[word] = true
+        if IsPalindrome(word, PalindromeStrict) {
+            words = append(words, word)
+        }
+    }
+    return words
+}
+
+// isWordRune reports whether r belongs inside a word: letters, numbers and
+// the combining marks attached to them
+func isWordRune(r rune) bool {
+    return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.M, r)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+    "strings"
+    "testing"
+)
+
+// TestPalindromeModes tests each normalization mode against the same inputs
+func TestPalindromeModes(t *testing.T) {
+    cases := []struct {
+        input    string
+        mode     PalindromeMode
+        expected bool
+    }{
+        {"Madam", PalindromeStrict, false},
+        {"madam", PalindromeStrict, true},
+        {"Never odd or even", PalindromeIgnoreWhitespace, true},
+        {"A man, a plan, a canal: Panama", PalindromeIgnoreWhitespace, false},
+        {"A man, a plan, a canal: Panama", PalindromeAlphanumeric, true},
+        {"Ésope reste ici et se repose", PalindromeAlphanumeric, false},
+        {"Ésope reste ici et se repose", PalindromeAccentInsensitive, true},
+        {"", PalindromeStrict, true},
+    }
+    for _, tc := range cases {
+        if got := IsPalindrome(tc.input, tc.mode); got != tc.expected {
+            t.Errorf("IsPalindrome(%q, %s) = %t, want %t", tc.input, tc.mode, got, tc.expected)
+        }
+    }
+}
+
+// TestParsePalindromeMode tests mode validation and the default
+func TestParsePalindromeMode(t *testing.T) {
+    if mode, err := ParsePalindromeMode(""); err != nil || mode != DefaultPalindromeMode {
+        t.Errorf("Expected default mode, got %q, %v", mode, err)
+    }
+    if mode, err := ParsePalindromeMode("alphanumeric-only"); err != nil || mode != PalindromeAlphanumeric {
+        t.Errorf("Expected alphanumeric-only, got %q, %v", mode, err)
+    }
+    if _, err := ParsePalindromeMode("fuzzy"); err == nil {
+        t.Error("Expected an error for an unknown mode")
+    }
+    
+    result, err := AnalyzeStringWithOptions("Was it a car or a cat I saw?", Options{PalindromeMode: PalindromeAlphanumeric})
+    if err != nil {
+        t.Fatalf("AnalyzeStringWithOptions failed: %v", err)
+    }
+    if !result.IsPalindrome || result.PalindromeMode != "alphanumeric-only" {
+        t.Errorf("Expected alphanumeric-only palindrome, got %t (%s)", result.IsPalindrome, result.PalindromeMode)
+    }
+}
+
+// TestAnalyzePalindromes tests the substring and word metrics
+func TestAnalyzePalindromes(t *testing.T) {
+    metrics := AnalyzePalindromes("My Racecar is at noon")
+    if metrics.LongestPalindrome != "Racecar" || metrics.LongestPalindromeStart != 3 || metrics.LongestPalindromeEnd != 10 {
+        t.Errorf("Unexpected longest palindrome %q [%d, %d)", metrics.LongestPalindrome, metrics.LongestPalindromeStart, metrics.LongestPalindromeEnd)
+    }
+    if len(metrics.PalindromicWords) != 2 || metrics.PalindromicWords[0] != "racecar" || metrics.PalindromicWords[1] != "noon" {
+        t.Errorf("Unexpected palindromic words %v", metrics.PalindromicWords)
+    }
+    
+    // "aaa" has a, aa, aaa; "abba" has a, b, bb, abba
+    if got := AnalyzePalindromes("aaa").DistinctPalindromes; got != 3 {
+        t.Errorf("Expected 3 distinct palindromes in 'aaa', got %d", got)
+    }
+    if got := AnalyzePalindromes("abba").DistinctPalindromes; got != 4 {
+        t.Errorf("Expected 4 distinct palindromes in 'abba', got %d", got)
+    }
+    
+    empty := AnalyzePalindromes("")
+    if empty.LongestPalindrome != "" || empty.DistinctPalindromes != 0 {
+        t.Errorf("Unexpected metrics for empty input: %+v", empty)
+    }
+}
+
+// TestAnalyzePalindromesBruteForce checks the linear algorithms against a naive count
+func TestAnalyzePalindromesBruteForce(t *testing.T) {
+    for _, input := range []string{"abacabadabacaba", "banana", "mississippi", "aabbaacc", "xyz"} {
+        runes := []rune(input)
+        distinct := make(map[string]bool)
+        longest := 0
+        for i := range runes {
+            for j := i + 1; j <= len(runes); j++ {
+                if IsPalindrome(string(runes[i:j]), PalindromeStrict) {
+                    distinct[string(runes[i:j])] = true
+                    longest = max(longest, j-i)
+                }
+            }
+        }
+        
+        metrics := AnalyzePalindromes(input)
+        if metrics.DistinctPalindromes != len(distinct) {
+            t.Errorf("%q: expected %d distinct palindromes, got %d", input, len(distinct), metrics.DistinctPalindromes)
+        }
+        if got := metrics.LongestPalindromeEnd - metrics.LongestPalindromeStart; got != longest {
+            t.Errorf("%q: expected longest palindrome of %d, got %d", input, longest, got)
+        }
+    }
+}
+
+// BenchmarkAnalyzePalindromes measures the metrics on a 1 MB input
+func BenchmarkAnalyzePalindromes(b *testing.B) {
+    input := strings.Repeat("abcba xyz ", 100*1024)
+    for i := 0; i < b.N; i++ {
+        AnalyzePalindromes(input)
+    }
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+    "math/big"
+    "net"
+    "regexp"
+    "sort"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// PII types reported in findings and accepted by the pii_types filter
+const (
+    PIIEmail      = "email"
+    PIIIPAddress  = "ip_address"
+    PIICreditCard = "credit_card"
+    PIIIBAN       = "iban"
+    PIINationalID = "national_id"
+    PIIPhone      = "phone"
+)
+
+// piiTypes is the order detectors run in; see findingSet
+var piiTypes = []string{PIIEmail, PIIIPAddress, PIIIBAN, PIICreditCard, PIINationalID, PIIPhone}
+
+// PIITypes lists every type of personal data the detector reports
+func PIITypes() []string {
+    return append([]string(nil), piiTypes...)
+}
+
+// piiRule finds candidates with a pattern; valid, when set, must accept the
+// matched text before it is reported
+type piiRule struct {
+    kind    string
+    pattern *regexp.Regexp
+    valid   func(match string) bool
+}
+
+var piiRules = []piiRule{
+    {PIIEmail, regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`), nil},
+    {PIIIPAddress, regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), validIP},
+    {PIIIPAddress, regexp.MustCompile(`[0-9A-Fa-f]*:[0-9A-Fa-f]*:[0-9A-Fa-f:]*`), validIPv6},
+    // IBANs come before cards so that card candidates cannot split an IBAN
+    {PIIIBAN, regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`), validIBAN},
+    {PIICreditCard, regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), validCardNumber},
+    // US social security numbers and UK national insurance numbers
+    {PIINationalID, regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), validSSN},
+    {PIINationalID, regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`), nil},
+    {PIIPhone, regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?|\b)\d{1,4}(?:[ .-]?\d{2,4}){1,4}\b`), validPhone},
+}
+
+// DetectPII returns emails, IP addresses, Luhn-valid card numbers, IBANs with
+// a valid checksum, national ID numbers and phone numbers, ordered by position
+func DetectPII(input string) []models.Finding {
+    findings := newFindingSet(input)
+    for _, rule := range piiRules {
+        for _, match := range rule.pattern.FindAllStringIndex(input, -1) {
+            if rule.valid != nil && !rule.valid(input[match[0]:match[1]]) {
+                continue
+            }
+            findings.add(rule.kind, match[0], match[1])
+        }
+    }
+    return findings.sorted()
+}
+
+func validIP(s string) bool {
+    return net.ParseIP(s) != nil
+}
+
+// validIPv6 also wants a digit and some length, so that words made of hex
+// letters around "::" are not taken for addresses
+func validIPv6(s string) bool {
+    return len(s) >= 6 && strings.ContainsAny(s, "0123456789") && validIP(s)
+}
+
+func digitsOf(s string) string {
+    return strings.Map(func(r rune) rune {
+        if r >= '0' && r <= '9' {
+            return r
+        }
+        return -1
+    }, s)
+}
+
+// validCardNumber checks the length and the Luhn checksum
+func validCardNumber(s string) bool {
+    digits := digitsOf(s)
+    if len(digits) < 13 || len(digits) > 19 {
+        return false
+    }
+    sum := 0
+    double := false
+    for i := len(digits) - 1; i >= 0; i-- {
+        d := int(digits[i] - '0')
+        if double {
+            d *= 2
+            if d > 9 {
+                d -= 9
+            }
+        }
+        sum += d
+        double = !double
+    }
+    return sum%10 == 0
+}
+
+// validIBAN checks the ISO 13616 mod-97 checksum
+func validIBAN(s string) bool {
+    iban := strings.ReplaceAll(s, " ", "")
+    if len(iban) < 15 || len(iban) > 34 {
+        return false
+    }
+    // Move the country code and check digits to the end, then letters become 10..35
+    var numeric strings.Builder
+    for _, r := range iban[4:] + iban[:4] {
+        if r >= 'A' && r <= 'Z' {
+            numeric.WriteString(big.NewInt(int64(r - 'A' + 10)).String())
+        } else {
+            numeric.WriteRune(r)
+        }
+    }
+    n, ok := new(big.Int).SetString(numeric.String(), 10)
+    return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// validSSN rejects area numbers that are never issued
+func validSSN(s string) bool {
+    area := s[:3]
+    return area != "000" && area != "666" && area[0] != '9' && s[4:6] != "00" && s[7:] != "0000"
+}
+
+// validPhone wants an international prefix or ten digits or more, which
+// keeps dates, times and short numbers out
+func validPhone(s string) bool {
+    digits := len(digitsOf(s))
+    if strings.HasPrefix(s, "+") {
+        return digits >= 8 && digits <= 15
+    }
+    return digits >= 10 && digits <= 15
+}
+
+// piiAnalyzer reports personal data and a copy of the value with it redacted
+type piiAnalyzer struct{}
+
+func (piiAnalyzer) Name() string    { return "pii" }
+func (piiAnalyzer) Version() string { return "1" }
+
+func (piiAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.PIIFindings = DetectPII(input)
+    seen := make(map[string]bool)
+    result.PIITypes = make([]string, 0)
+    for _, finding := range result.PIIFindings {
+        if !seen[finding.Type] {
+            seen[finding.Type] = true
+            result.PIITypes = append(result.PIITypes, finding.Type)
+        }
+    }
+    sort.Strings(result.PIITypes)
+    result.RedactedValue = Redact(input, result.PIIFindings)
+    return nil
+}
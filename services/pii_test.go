@@ -0,0 +1,60 @@
+package services
+
+import (
+    "testing"
+)
+
+// TestDetectPII tests each detector and its validation
+func TestDetectPII(t *testing.T) {
+    cases := []struct {
+        input string
+        kind  string
+        start int
+        end   int
+    }{
+        {"mail jane.doe@example.co.uk now", PIIEmail, 5, 27},
+        {"from 192.168.1.20", PIIIPAddress, 5, 17},
+        {"v6 2001:db8::ff00:42:8329", PIIIPAddress, 3, 25},
+        {"card 4111 1111 1111 1111", PIICreditCard, 5, 24},
+        {"IBAN DE89 3704 0044 0532 0130 00", PIIIBAN, 5, 32},
+        {"SSN 123-45-6789", PIINationalID, 4, 15},
+        {"NI AB 12 34 56 C", PIINationalID, 3, 16},
+        {"call +44 20 7946 0958", PIIPhone, 5, 21},
+        {"téléphone (555) 123-4567", PIIPhone, 10, 24},
+    }
+    for _, tc := range cases {
+        findings := DetectPII(tc.input)
+        if len(findings) != 1 || findings[0].Type != tc.kind || findings[0].Start != tc.start || findings[0].End != tc.end {
+            t.Errorf("DetectPII(%q) = %+v, want one %s at [%d, %d)", tc.input, findings, tc.kind, tc.start, tc.end)
+        }
+    }
+    
+    for _, input := range []string{
+        "card 4111 1111 1111 1112",         // fails Luhn
+        "IBAN DE89 3704 0044 0532 0130 02", // fails mod 97
+        "SSN 666-45-6789",
+        "ip 999.1.1.1",
+        "met at 12:30:45 on 2024-01-21",
+        "dead::beef",
+    } {
+        if findings := DetectPII(input); len(findings) != 0 {
+            t.Errorf("DetectPII(%q) = %+v, want none", input, findings)
+        }
+    }
+}
+
+// TestPIIAnalyzer tests the reported types and the redacted value
+func TestPIIAnalyzer(t *testing.T) {
+    result := AnalyzeString("Email bob@example.com or call +1 415 555 0100, bob@example.com")
+    if len(result.PIIFindings) != 3 || len(result.PIITypes) != 2 || result.PIITypes[0] != PIIEmail || result.PIITypes[1] != PIIPhone {
+        t.Errorf("Unexpected findings %+v, types %v", result.PIIFindings, result.PIITypes)
+    }
+    expected := "Email [REDACTED:email] or call [REDACTED:phone], [REDACTED:email]"
+    if result.RedactedValue != expected {
+        t.Errorf("RedactedValue = %q, want %q", result.RedactedValue, expected)
+    }
+    
+    if clean := AnalyzeString("nothing to see"); clean.RedactedValue != "nothing to see" || len(clean.PIITypes) != 0 {
+        t.Errorf("Expected a clean value to be unchanged, got %+v", clean)
+    }
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+    "math"
+    "strings"
+    "unicode/utf8"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// readabilityAnalyzer computes sentence and syllable counts and the Flesch
+// readability scores. Syllables are estimated with English rules, so the
+// scores are only meaningful for English text.
+type readabilityAnalyzer struct{}
+
+func (readabilityAnalyzer) Name() string    { return "readability" }
+func (readabilityAnalyzer) Version() string { return "1" }
+
+func (readabilityAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    words := Words(input)
+    result.SentenceCount = CountSentences(input)
+    result.SyllableCount = 0
+    result.AverageWordLength = 0
+    result.LexicalDiversity = 0
+    result.FleschReadingEase = 0
+    result.FleschKincaidGrade = 0
+    if len(words) == 0 {
+        return nil
+    }
+    
+    letters := 0
+    distinct := make(map[string]bool)
+    for _, word := range words {
+        letters += utf8.RuneCountInString(word)
+        result.SyllableCount += CountSyllables(word)
+        distinct[word] = true
+    }
+    
+    wordCount := float64(len(words))
+    wordsPerSentence := wordCount / float64(result.SentenceCount)
+    syllablesPerWord := float64(result.SyllableCount) / wordCount
+    result.AverageWordLength = round2(float64(letters) / wordCount)
+    result.LexicalDiversity = round2(float64(len(distinct)) / wordCount)
+    result.FleschReadingEase = round2(206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord)
+    result.FleschKincaidGrade = round2(0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59)
+    return nil
+}
+
+// CountSentences counts runs of text ended by '.', '!', '?' or their CJK
+// forms. Trailing text without a terminator counts as a sentence, and
+// fragments with no letters or digits, such as "...", do not.
+func CountSentences(input string) int {
+    sentences := 0
+    hasWord := false
+    for _, r := range input {
+        switch r {
+        case '.', '!', '?', '…', '。', '！', '？':
+            if hasWord {
+                sentences++
+                hasWord = false
+            }
+        default:
+            if isWordRune(r) {
+                hasWord = true
+            }
+        }
+    }
+    if hasWord {
+        sentences++
+    }
+    return sentences
+}
+
+// CountSyllables estimates the syllables in an English word by counting
+// vowel groups, dropping a silent final 'e'. Every word has at least one.
+func CountSyllables(word string) int {
+    word = strings.ToLower(word)
+    count := 0
+    previousVowel := false
+    for _, r := range word {
+        vowel := strings.ContainsRune("aeiouy", r)
+        if vowel && !previousVowel {
+            count++
+        }
+        previousVowel = vowel
+    }
+    if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && count > 1 {
+        count--
+    }
+    if count == 0 {
+        count = 1
+    }
+    return count
+}
+
+func round2(v float64) float64 {
+    return math.Round(v*100) / 100
+}
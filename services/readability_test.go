@@ -0,0 +1,55 @@
+package services
+
+import (
+    "testing"
+)
+
+// TestCountSentences tests sentence splitting on terminators
+func TestCountSentences(t *testing.T) {
+    cases := map[string]int{
+        "":                              0,
+        "No terminator":                 1,
+        "One. Two! Three?":              3,
+        "Wait... what?!":                2,
+        "...":                           0,
+        "你好。再见！":                        2,
+    }
+    for input, expected := range cases {
+        if got := CountSentences(input); got != expected {
+            t.Errorf("CountSentences(%q) = %d, want %d", input, got, expected)
+        }
+    }
+}
+
+// TestCountSyllables tests the English syllable heuristic
+func TestCountSyllables(t *testing.T) {
+    cases := map[string]int{
+        "cat": 1, "make": 1, "table": 2, "reading": 2, "beautiful": 3, "rhythm": 1, "the": 1,
+    }
+    for word, expected := range cases {
+        if got := CountSyllables(word); got != expected {
+            t.Errorf("CountSyllables(%q) = %d, want %d", word, got, expected)
+        }
+    }
+}
+
+// TestReadabilityScores tests that simple text scores as easier than dense text
+func TestReadabilityScores(t *testing.T) {
+    simple := AnalyzeString("The cat sat on the mat. The dog ran.")
+    dense := AnalyzeString("Institutional considerations necessitate comprehensive organizational restructuring initiatives.")
+    
+    if simple.SentenceCount != 2 || simple.SyllableCount != 9 {
+        t.Errorf("Expected 2 sentences and 9 syllables, got %d and %d", simple.SentenceCount, simple.SyllableCount)
+    }
+    if simple.AverageWordLength != 2.89 || simple.LexicalDiversity != 0.78 {
+        t.Errorf("Unexpected average word length %v or lexical diversity %v", simple.AverageWordLength, simple.LexicalDiversity)
+    }
+    if simple.FleschReadingEase <= dense.FleschReadingEase || simple.FleschKincaidGrade >= dense.FleschKincaidGrade {
+        t.Errorf("Expected simple text to read easier: ease %v vs %v, grade %v vs %v",
+            simple.FleschReadingEase, dense.FleschReadingEase, simple.FleschKincaidGrade, dense.FleschKincaidGrade)
+    }
+    
+    if empty := AnalyzeString(""); empty.FleschReadingEase != 0 || empty.LexicalDiversity != 0 {
+        t.Errorf("Expected zero scores for empty input, got %+v", empty)
+    }
+}
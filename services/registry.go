@@ -0,0 +1,218 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// Analyzer computes one named group of metrics. Built-in analyzers fill the
+// typed fields of models.AnalysisResult; custom ones write under their own
+// name in result.CustomProperties, which is stored and returned as part of
+// "properties". Bump Version whenever the output for the same input changes.
+type Analyzer interface {
+    Name() string
+    Version() string
+    Analyze(input string, opts Options, result *models.AnalysisResult) error
+}
+
+// AnalyzerInfo describes a registered analyzer
+type AnalyzerInfo struct {
+    Name     string `json:"name"`
+    Version  string `json:"version"`
+    Enabled  bool   `json:"enabled"`
+    Required bool   `json:"required"`
+}
+
+// Registry runs analyzers in registration order. It is safe for concurrent use.
+type Registry struct {
+    mu        sync.RWMutex
+    analyzers []Analyzer
+    disabled  map[string]bool
+    required  map[string]bool
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+    return &Registry{
+        disabled: make(map[string]bool),
+        required: make(map[string]bool),
+    }
+}
+
+// DefaultRegistry holds the built-in analyzers plus anything registered at
+// startup. AnalyzeString and AnalyzeStringWithOptions run it.
+var DefaultRegistry = newDefaultRegistry()
+
+// Register adds an analyzer to DefaultRegistry
+func Register(analyzer Analyzer) error {
+    return DefaultRegistry.Register(analyzer)
+}
+
+// Register adds an analyzer. Names must be unique and non-empty.
+func (r *Registry) Register(analyzer Analyzer) error {
+    if analyzer.Name() == "" {
+        return errors.New("analyzer name must not be empty")
+    }
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    for _, existing := range r.analyzers {
+        if existing.Name() == analyzer.Name() {
+            return fmt.Errorf("analyzer %q is already registered", analyzer.Name())
+        }
+    }
+    r.analyzers = append(r.analyzers, analyzer)
+    return nil
+}
+
+// registerRequired adds an analyzer that cannot be disabled
+func (r *Registry) registerRequired(analyzer Analyzer) error {
+    if err := r.Register(analyzer); err != nil {
+        return err
+    }
+    r.mu.Lock()
+    r.required[analyzer.Name()] = true
+    r.mu.Unlock()
+    return nil
+}
+
+// SetEnabled turns an analyzer on or off. Required analyzers, such as the
+// hash that provides the ID, cannot be disabled.
+func (r *Registry) SetEnabled(name string, enabled bool) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    known := false
+    for _, analyzer := range r.analyzers {
+        if analyzer.Name() == name {
+            known = true
+            break
+        }
+    }
+    if !known {
+        return fmt.Errorf("unknown analyzer %q", name)
+    }
+    if !enabled && r.required[name] {
+        return fmt.Errorf("analyzer %q is required and cannot be disabled", name)
+    }
+    if enabled {
+        delete(r.disabled, name)
+    } else {
+        r.disabled[name] = true
+    }
+    return nil
+}
+
+// Enabled reports whether name is registered and switched on
+func (r *Registry) Enabled(name string) bool {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    for _, analyzer := range r.analyzers {
+        if analyzer.Name() == name {
+            return !r.disabled[name]
+        }
+    }
+    return false
+}
+
+// Analyzers lists the registered analyzers in run order
+func (r *Registry) Analyzers() []AnalyzerInfo {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    infos := make([]AnalyzerInfo, len(r.analyzers))
+    for i, analyzer := range r.analyzers {
+        infos[i] = AnalyzerInfo{
+            Name:     analyzer.Name(),
+            Version:  analyzer.Version(),
+            Enabled:  !r.disabled[analyzer.Name()],
+            Required: r.required[analyzer.Name()],
+        }
+    }
+    return infos
+}
+
+// Analyze runs every enabled analyzer over input. A failing or panicking
+// analyzer does not abort the others; its error is recorded under its name
+// in CustomProperties and it is left out of AnalyzerVersions. A required
+// analyzer is the exception: without it, such as without the hash that
+// provides the ID, the result must not be stored, so its failure is returned.
+func (r *Registry) Analyze(input string, opts Options) (models.AnalysisResult, error) {
+    if opts.PalindromeMode == "" {
+        opts.PalindromeMode = DefaultPalindromeMode
+    }
+
+    result := models.AnalysisResult{
+        Value: input,
+        AnalyzerVersions: make(map[string]string),
+        CreatedAt: time.Now().UTC().Format(time.RFC3339),
+    }
+
+    r.mu.RLock()
+    analyzers := make([]Analyzer, 0, len(r.analyzers))
+    for _, analyzer := range r.analyzers {
+        if !r.disabled[analyzer.Name()] {
+            analyzers = append(analyzers, analyzer)
+        }
+    }
+    r.mu.RUnlock()
+
+    for _, analyzer := range analyzers {
+        if err := runAnalyzer(analyzer, input, opts, &result); err != nil {
+            if r.isRequired(analyzer.Name()) {
+                return result, fmt.Errorf("required analyzer %q failed: %w", analyzer.Name(), err)
+            }
+            if result.CustomProperties == nil {
+                result.CustomProperties = make(map[string]interface{})
+            }
+            result.CustomProperties[analyzer.Name()] = map[string]interface{}{"error": err.Error()}
+            continue
+        }
+        result.AnalyzerVersions[analyzer.Name()] = analyzer.Version()
+    }
+
+    return result, nil
+}
+
+func (r *Registry) isRequired(name string) bool {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return r.required[name]
+}
+
+func runAnalyzer(analyzer Analyzer, input string, opts Options, result *models.AnalysisResult) (err error) {
+    defer func() {
+        if recovered := recover(); recovered != nil {
+            err = fmt.Errorf("analyzer panicked: %v", recovered)
+        }
+    }()
+    return analyzer.Analyze(input, opts, result)
+}
+
+// customAnalyzer adapts a plain function into an Analyzer
+type customAnalyzer struct {
+    name    string
+    version string
+    fn      func(input string) (interface{}, error)
+}
+
+// NewCustomAnalyzer wraps fn as an analyzer whose return value is stored
+// under name in the result properties. The value must encode to JSON.
+func NewCustomAnalyzer(name, version string, fn func(input string) (interface{}, error)) Analyzer {
+    return &customAnalyzer{name: name, version: version, fn: fn}
+}
+
+func (a *customAnalyzer) Name() string    { return a.name }
+func (a *customAnalyzer) Version() string { return a.version }
+
+func (a *customAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    value, err := a.fn(input)
+    if err != nil {
+        return err
+    }
+    if result.CustomProperties == nil {
+        result.CustomProperties = make(map[string]interface{})
+    }
+    result.CustomProperties[a.name] = value
+    return nil
+}
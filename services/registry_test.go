@@ -0,0 +1,109 @@
+package services
+
+import (
+    "errors"
+    "strings"
+    "testing"
+)
+
+// TestRegistryCustomAnalyzer tests that custom output lands in CustomProperties
+func TestRegistryCustomAnalyzer(t *testing.T) {
+    registry := newDefaultRegistry()
+    vowels := NewCustomAnalyzer("vowel_count", "2", func(input string) (interface{}, error) {
+        count := 0
+        for _, r := range strings.ToLower(input) {
+            if strings.ContainsRune("aeiou", r) {
+                count++
+            }
+        }
+        return count, nil
+    })
+    if err := registry.Register(vowels); err != nil {
+        t.Fatalf("Register failed: %v", err)
+    }
+    if err := registry.Register(vowels); err == nil {
+        t.Error("Expected duplicate registration to fail")
+    }
+    
+    result, err := registry.Analyze("banana", Options{})
+    if err != nil {
+        t.Fatalf("Analyze failed: %v", err)
+    }
+    if result.CustomProperties["vowel_count"] != 3 {
+        t.Errorf("Expected vowel_count 3, got %v", result.CustomProperties["vowel_count"])
+    }
+    if result.AnalyzerVersions["vowel_count"] != "2" || result.AnalyzerVersions["hash"] == "" {
+        t.Errorf("Unexpected analyzer versions %v", result.AnalyzerVersions)
+    }
+}
+
+// TestRegistryEnableDisable tests switching analyzers off and the required hash
+func TestRegistryEnableDisable(t *testing.T) {
+    registry := newDefaultRegistry()
+    if err := registry.SetEnabled("frequency", false); err != nil {
+        t.Fatalf("SetEnabled failed: %v", err)
+    }
+    if err := registry.SetEnabled("hash", false); err == nil {
+        t.Error("Expected disabling the required hash analyzer to fail")
+    }
+    if err := registry.SetEnabled("nope", false); err == nil {
+        t.Error("Expected an error for an unknown analyzer")
+    }
+    if registry.Enabled("frequency") || !registry.Enabled("length") || registry.Enabled("nope") {
+        t.Error("Enabled does not match the analyzers switched on")
+    }
+    
+    result, err := registry.Analyze("hello", Options{})
+    if err != nil {
+        t.Fatalf("Analyze failed: %v", err)
+    }
+    if _, ran := result.AnalyzerVersions["frequency"]; ran || result.UniqueCharacters != 0 {
+        t.Errorf("Expected frequency analyzer to be skipped, got %+v", result)
+    }
+    if result.ID == "" || result.Length != 5 {
+        t.Errorf("Expected the other analyzers to run, got %+v", result)
+    }
+}
+
+// TestRegistryFailingAnalyzer tests that errors and panics are contained
+func TestRegistryFailingAnalyzer(t *testing.T) {
+    registry := newDefaultRegistry()
+    registry.Register(NewCustomAnalyzer("broken", "1", func(string) (interface{}, error) {
+        return nil, errors.New("boom")
+    }))
+    registry.Register(NewCustomAnalyzer("panicky", "1", func(string) (interface{}, error) {
+        panic("oops")
+    }))
+    
+    result, err := registry.Analyze("hello", Options{})
+    if err != nil {
+        t.Fatalf("Analyze failed: %v", err)
+    }
+    for _, name := range []string{"broken", "panicky"} {
+        if _, ran := result.AnalyzerVersions[name]; ran {
+            t.Errorf("Failed analyzer %s should not be recorded as run", name)
+        }
+        if _, ok := result.CustomProperties[name].(map[string]interface{})["error"]; !ok {
+            t.Errorf("Expected an error entry for %s, got %v", name, result.CustomProperties[name])
+        }
+    }
+    if result.ID == "" {
+        t.Error("Expected built-in analyzers to still run")
+    }
+}
+
+// TestRegistryRequiredAnalyzerFails tests that a failing required analyzer
+// aborts the analysis instead of leaving an empty ID
+func TestRegistryRequiredAnalyzerFails(t *testing.T) {
+    registry := newDefaultRegistry()
+    if _, err := registry.Analyze("hello", Options{IDHash: "nope"}); err == nil || !strings.Contains(err.Error(), `"hash"`) {
+        t.Errorf("Expected the hash analyzer to fail for an unknown ID hash, got %v", err)
+    }
+    
+    registry.registerRequired(NewCustomAnalyzer("essential", "1", func(string) (interface{}, error) {
+        panic("oops")
+    }))
+    if _, err := registry.Analyze("hello", Options{}); err == nil || !strings.Contains(err.Error(), "panicked") {
+        t.Errorf("Expected the panic of a required analyzer to be returned, got %v", err)
+    }
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+    "sort"
+    "strings"
+    "unicode"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/rivo/uniseg"
+)
+
+// Character categories reported in CategoryCounts
+const (
+    CategoryLetter      = "letter"
+    CategoryMark        = "mark"
+    CategoryDigit       = "digit"
+    CategoryNumber      = "number" // numerals other than decimal digits, such as Ⅻ or ½
+    CategoryPunctuation = "punctuation"
+    CategorySymbol      = "symbol"
+    CategoryWhitespace  = "whitespace"
+    CategoryControl     = "control"
+    CategoryEmoji       = "emoji"
+    CategoryOther       = "other"
+)
+
+// commonScripts are tried before the full unicode.Scripts table
+var commonScripts = []string{"Latin", "Cyrillic", "Greek", "Han", "Arabic", "Hebrew", "Hiragana", "Katakana", "Hangul", "Devanagari", "Thai"}
+
+// scriptNames is every script in unicode.Scripts, sorted for a stable lookup order
+var scriptNames = func() []string {
+    names := make([]string, 0, len(unicode.Scripts))
+    for name := range unicode.Scripts {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}()
+
+// ScriptOf returns the Unicode script of r, or "" for characters shared
+// between scripts (Common and Inherited), such as digits and punctuation
+func ScriptOf(r rune) string {
+    for _, name := range commonScripts {
+        if unicode.Is(unicode.Scripts[name], r) {
+            return name
+        }
+    }
+    if unicode.Is(unicode.Common, r) || unicode.Is(unicode.Inherited, r) {
+        return ""
+    }
+    for _, name := range scriptNames {
+        if unicode.Is(unicode.Scripts[name], r) {
+            return name
+        }
+    }
+    return ""
+}
+
+// LookupScript returns the canonical name of a script, matched case-insensitively
+func LookupScript(name string) (string, bool) {
+    for _, known := range scriptNames {
+        if strings.EqualFold(known, name) {
+            return known, true
+        }
+    }
+    return "", false
+}
+
+// pictographic approximates the Extended_Pictographic property from UTS #51
+var pictographic = &unicode.RangeTable{
+    R16: []unicode.Range16{
+        {0x00a9, 0x00ae, 5},
+        {0x203c, 0x2049, 13},
+        {0x2122, 0x2139, 23},
+        {0x2194, 0x2199, 1},
+        {0x21a9, 0x21aa, 1},
+        {0x231a, 0x231b, 1},
+        {0x2328, 0x23cf, 167},
+        {0x23e9, 0x23f3, 1},
+        {0x23f8, 0x23fa, 1},
+        {0x24c2, 0x25aa, 232},
+        {0x25ab, 0x25b6, 11},
+        {0x25c0, 0x25c0, 1},
+        {0x25fb, 0x25fe, 1},
+        {0x2600, 0x27bf, 1},
+        {0x2934, 0x2935, 1},
+        {0x2b05, 0x2b07, 1},
+        {0x2b1b, 0x2b1c, 1},
+        {0x2b50, 0x2b55, 5},
+        {0x3030, 0x303d, 13},
+        {0x3297, 0x3299, 2},
+    },
+    R32: []unicode.Range32{
+        {0x1f000, 0x1faff, 1},
+    },
+}
+
+// isEmoji reports whether a grapheme cluster renders as an emoji. Symbols
+// such as © or ↔ only count when followed by the emoji variation selector.
+func isEmoji(cluster string) bool {
+    for _, r := range cluster {
+        if !unicode.Is(pictographic, r) {
+            continue
+        }
+        if r >= 0x1f000 || (r >= 0x2600 && r <= 0x27bf) || strings.ContainsRune(cluster, '\ufe0f') {
+            return true
+        }
+    }
+    return false
+}
+
+// categoryOf maps a rune onto one of the reported categories
+func categoryOf(r rune) string {
+    switch {
+    case unicode.IsLetter(r):
+        return CategoryLetter
+    case unicode.IsMark(r):
+        return CategoryMark
+    case unicode.IsDigit(r):
+        return CategoryDigit
+    case unicode.IsNumber(r):
+        return CategoryNumber
+    case unicode.IsSpace(r):
+        return CategoryWhitespace
+    case unicode.IsControl(r):
+        return CategoryControl
+    case unicode.IsPunct(r):
+        return CategoryPunctuation
+    case unicode.IsSymbol(r):
+        return CategorySymbol
+    }
+    return CategoryOther
+}
+
+// compatibleScripts are written together within one word, so mixing them
+// is not suspicious
+var compatibleScripts = map[string]string{
+    "Han": "Japanese", "Hiragana": "Japanese", "Katakana": "Japanese",
+}
+
+// IsMixedScript reports whether any single word combines letters from
+// different scripts, the usual sign of a homoglyph such as a Cyrillic 'а'
+// inside a Latin word. Han, Hiragana and Katakana count as one script.
+func IsMixedScript(input string) bool {
+    for _, word := range Words(input) {
+        first := ""
+        for _, r := range word {
+            script := ScriptOf(r)
+            if script == "" {
+                continue
+            }
+            if group, ok := compatibleScripts[script]; ok {
+                script = group
+            }
+            if first == "" {
+                first = script
+            } else if script != first {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// scriptsAnalyzer breaks the string down by character category and script
+type scriptsAnalyzer struct{}
+
+func (scriptsAnalyzer) Name() string    { return "scripts" }
+func (scriptsAnalyzer) Version() string { return "1" }
+
+func (scriptsAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.CategoryCounts = make(map[string]int)
+    result.ScriptCounts = make(map[string]int)
+    
+    // Emoji are counted per grapheme cluster, since one emoji may be several runes
+    graphemes := uniseg.NewGraphemes(input)
+    for graphemes.Next() {
+        cluster := graphemes.Str()
+        if isEmoji(cluster) {
+            result.CategoryCounts[CategoryEmoji]++
+            continue
+        }
+        for _, r := range cluster {
+            result.CategoryCounts[categoryOf(r)]++
+            if script := ScriptOf(r); script != "" {
+                result.ScriptCounts[script]++
+            }
+        }
+    }
+    
+    result.EmojiCount = result.CategoryCounts[CategoryEmoji]
+    result.MixedScript = IsMixedScript(input)
+    return nil
+}
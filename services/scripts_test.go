@@ -0,0 +1,53 @@
+package services
+
+import (
+    "testing"
+)
+
+// TestCategoryAndScriptCounts tests the per-category and per-script breakdown
+func TestCategoryAndScriptCounts(t *testing.T) {
+    result := AnalyzeString("Привет, world 42! 👍🏽 ©")
+    
+    expected := map[string]int{
+        CategoryLetter: 11, CategoryDigit: 2, CategoryPunctuation: 2,
+        CategoryWhitespace: 4, CategoryEmoji: 1, CategorySymbol: 1,
+    }
+    for category, count := range expected {
+        if result.CategoryCounts[category] != count {
+            t.Errorf("CategoryCounts[%s] = %d, want %d (%v)", category, result.CategoryCounts[category], count, result.CategoryCounts)
+        }
+    }
+    if result.ScriptCounts["Cyrillic"] != 6 || result.ScriptCounts["Latin"] != 5 || len(result.ScriptCounts) != 2 {
+        t.Errorf("Unexpected script counts %v", result.ScriptCounts)
+    }
+    if result.EmojiCount != 1 || result.MixedScript {
+        t.Errorf("Expected one emoji and no mixed-script word, got %d, %t", result.EmojiCount, result.MixedScript)
+    }
+}
+
+// TestIsMixedScript tests homoglyph detection within words
+func TestIsMixedScript(t *testing.T) {
+    cases := map[string]bool{
+        "paypal":              false,
+        "pаypal":              true, // Cyrillic а
+        "hello мир":           false,
+        "東京タワーへようこそ":          false,
+        "Ωmega":               true,
+        "ABC123":              false,
+    }
+    for input, expected := range cases {
+        if got := IsMixedScript(input); got != expected {
+            t.Errorf("IsMixedScript(%q) = %t, want %t", input, got, expected)
+        }
+    }
+}
+
+// TestLookupScript tests case-insensitive script names
+func TestLookupScript(t *testing.T) {
+    if name, ok := LookupScript("cyrillic"); !ok || name != "Cyrillic" {
+        t.Errorf("LookupScript(cyrillic) = %q, %t", name, ok)
+    }
+    if _, ok := LookupScript("Klingon"); ok {
+        t.Error("Expected Klingon to be unknown")
+    }
+}
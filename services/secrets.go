@@ -0,0 +1,152 @@
+package services
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// SecretPolicy says what POST /strings does with values that contain secrets
+type SecretPolicy string
+
+const (
+    // SecretPolicyAllow stores the value as submitted and only flags it
+    SecretPolicyAllow SecretPolicy = "allow"
+    // SecretPolicyRedact replaces each finding with a marker before storing
+    SecretPolicyRedact SecretPolicy = "redact"
+    // SecretPolicyReject refuses the value
+    SecretPolicyReject SecretPolicy = "reject"
+)
+
+// secretPolicies is ordered from least to most strict
+var secretPolicies = []SecretPolicy{SecretPolicyAllow, SecretPolicyRedact, SecretPolicyReject}
+
+// ParseSecretPolicy validates a policy name; empty selects allow
+func ParseSecretPolicy(name string) (SecretPolicy, error) {
+    if name == "" {
+        return SecretPolicyAllow, nil
+    }
+    names := make([]string, len(secretPolicies))
+    for i, policy := range secretPolicies {
+        if string(policy) == name {
+            return policy, nil
+        }
+        names[i] = string(policy)
+    }
+    return "", fmt.Errorf("unknown secret policy '%s' (allowed: %s)", name, strings.Join(names, ", "))
+}
+
+// Stricter returns whichever of the two policies is stricter
+func (policy SecretPolicy) Stricter(other SecretPolicy) SecretPolicy {
+    for i := len(secretPolicies) - 1; i >= 0; i-- {
+        if policy == secretPolicies[i] || other == secretPolicies[i] {
+            return secretPolicies[i]
+        }
+    }
+    return SecretPolicyAllow
+}
+
+// AnalyzeWithPolicy analyzes input and applies policy to any secrets found:
+// redact re-analyzes the redacted value but keeps contains_secret and the
+// findings, marked as redacted, so the row still records what was removed;
+// reject reports rejected so that the caller does not store it. An error
+// means that a required analyzer failed and nothing must be stored.
+func AnalyzeWithPolicy(input string, opts Options, policy SecretPolicy) (result models.AnalysisResult, rejected bool, err error) {
+    result, err = AnalyzeStringWithOptions(input, opts)
+    if err != nil || !result.ContainsSecret {
+        return result, false, err
+    }
+    switch policy {
+    case SecretPolicyReject:
+        return result, true, nil
+    case SecretPolicyRedact:
+        findings := result.SecretFindings
+        if result, err = AnalyzeStringWithOptions(Redact(input, findings), opts); err != nil {
+            return result, false, err
+        }
+        result.ContainsSecret = true
+        result.SecretFindings = make([]models.Finding, len(findings))
+        for i, finding := range findings {
+            finding.Redacted = true
+            result.SecretFindings[i] = finding
+        }
+    }
+    return result, false, nil
+}
+
+// secretRule is a pattern for one kind of credential. When group is set only
+// that submatch is reported, and minEntropy filters out placeholder values.
+type secretRule struct {
+    kind       string
+    pattern    *regexp.Regexp
+    group      int
+    minEntropy float64
+}
+
+// secretRules run in order; a later rule never reports a span that overlaps
+// an earlier finding, so specific rules come before generic ones. A private
+// key runs from its header through the matching footer, or to the end of the
+// input when the footer is missing, so that redaction removes the key body.
+var secretRules = []secretRule{
+    {kind: "private_key", pattern: regexp.MustCompile(`(?s)-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----.*?(?:-----END [A-Z ]*PRIVATE KEY(?: BLOCK)?-----|\z)`)},
+    {kind: "aws_access_key", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`)},
+    {kind: "aws_secret_key", pattern: regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|key).{0,20}?['"\s:=]+([A-Za-z0-9/+]{40})\b`), group: 1},
+    {kind: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`)},
+    {kind: "github_token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+    {kind: "slack_token", pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+    {kind: "stripe_key", pattern: regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`)},
+    {kind: "google_api_key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+    {kind: "generic_secret", pattern: regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|passw(?:or)?d|pwd|auth)\b['"]?\s*[:=]\s*['"]?([^\s'",;]{8,})`), group: 1, minEntropy: 3},
+    {kind: "high_entropy_token", pattern: regexp.MustCompile(`[A-Za-z0-9+/_-]{20,}={0,2}`), minEntropy: highEntropySecretBits},
+}
+
+// highEntropySecretBits is the bits per character above which an unlabelled
+// token that mixes letters and digits is treated as a likely secret
+const highEntropySecretBits = 4.0
+
+// DetectSecrets returns likely credentials in input, ordered by position.
+// Offsets are rune offsets; the matched text itself is never included.
+func DetectSecrets(input string) []models.Finding {
+    findings := newFindingSet(input)
+    for _, rule := range secretRules {
+        for _, match := range rule.pattern.FindAllStringSubmatchIndex(input, -1) {
+            start, end := match[0], match[1]
+            if rule.group > 0 {
+                start, end = match[2*rule.group], match[2*rule.group+1]
+            }
+            if start < 0 {
+                continue
+            }
+            text := input[start:end]
+            if rule.minEntropy > 0 {
+                if entropy, _ := ShannonEntropy(text); entropy < rule.minEntropy {
+                    continue
+                }
+            }
+            if rule.kind == "high_entropy_token" && !mixesLettersAndDigits(text) {
+                continue
+            }
+            findings.add(rule.kind, start, end)
+        }
+    }
+    return findings.sorted()
+}
+
+func mixesLettersAndDigits(s string) bool {
+    return strings.ContainsAny(s, "0123456789") && strings.IndexFunc(s, func(r rune) bool {
+        return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+    }) >= 0
+}
+
+// secretsAnalyzer flags strings that look like they contain credentials
+type secretsAnalyzer struct{}
+
+func (secretsAnalyzer) Name() string    { return "secrets" }
+func (secretsAnalyzer) Version() string { return "2" }
+
+func (secretsAnalyzer) Analyze(input string, opts Options, result *models.AnalysisResult) error {
+    result.SecretFindings = DetectSecrets(input)
+    result.ContainsSecret = len(result.SecretFindings) > 0
+    return nil
+}
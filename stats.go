@@ -0,0 +1,97 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+    "text/tabwriter"
+    "github.com/holladworld/string-analyzer/storage"
+)
+
+// runStats implements "stats [flags]": counts, lengths and language and
+// script breakdowns over the stored strings, optionally filtered
+func runStats(args []string) error {
+    flags := newFlagSet("stats", "")
+    format := outputFlag(flags)
+    filter := filterFlag(flags, "only count strings")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if flags.NArg() != 0 {
+        flags.Usage()
+        return errors.New("stats takes no arguments")
+    }
+    if err := checkOutputFormat(*format); err != nil {
+        return err
+    }
+    filters, err := parseFilterFlag(*filter)
+    if err != nil {
+        return err
+    }
+    
+    cfg, _, err := loadConfig()
+    if err != nil {
+        return err
+    }
+    repo, err := openStore(cfg)
+    if err != nil {
+        return err
+    }
+    defer repo.Close()
+    
+    stats, err := storage.CollectStats(repo, filters)
+    if err != nil {
+        return err
+    }
+    if *format == "table" {
+        return writeStatsTable(os.Stdout, stats)
+    }
+    encoder := json.NewEncoder(os.Stdout)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(stats)
+}
+
+func writeStatsTable(w io.Writer, stats storage.Stats) error {
+    table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+    rows := [][2]string{
+        {"count", strconv.Itoa(stats.Count)},
+        {"palindromes", strconv.Itoa(stats.Palindromes)},
+        {"with_secrets", strconv.Itoa(stats.WithSecrets)},
+        {"with_pii", strconv.Itoa(stats.WithPII)},
+        {"min_length", strconv.Itoa(stats.MinLength)},
+        {"max_length", strconv.Itoa(stats.MaxLength)},
+        {"average_length", strconv.FormatFloat(stats.AverageLength, 'f', 2, 64)},
+        {"languages", countList(stats.Languages)},
+        {"scripts", countList(stats.Scripts)},
+        {"oldest_created_at", stats.OldestCreatedAt},
+        {"newest_created_at", stats.NewestCreatedAt},
+    }
+    for _, row := range rows {
+        fmt.Fprintf(table, "%s\t%s\n", row[0], row[1])
+    }
+    return table.Flush()
+}
+
+// countList renders counts as "a=2, b=1", most frequent first
+func countList(counts map[string]int) string {
+    keys := make([]string, 0, len(counts))
+    for key := range counts {
+        keys = append(keys, key)
+    }
+    sort.Slice(keys, func(i, j int) bool {
+        if counts[keys[i]] != counts[keys[j]] {
+            return counts[keys[i]] > counts[keys[j]]
+        }
+        return keys[i] < keys[j]
+    })
+    parts := make([]string, len(keys))
+    for i, key := range keys {
+        parts[i] = key + "=" + strconv.Itoa(counts[key])
+    }
+    return strings.Join(parts, ", ")
+}
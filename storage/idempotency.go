@@ -0,0 +1,25 @@
+package storage
+
+import (
+    "time"
+)
+
+// IdempotentResponse is a response recorded under an Idempotency-Key.
+// Fingerprint identifies the request it answered, so a key reused for a
+// different request can be told apart from a retry.
+type IdempotentResponse struct {
+    Fingerprint string
+    Status      int
+    ContentType string
+    Body        []byte
+    ExpiresAt   time.Time
+}
+
+// IdempotencyStore keeps recorded responses until they expire
+type IdempotencyStore interface {
+    // GetIdempotentResponse returns the response stored under key unless it expired before now
+    GetIdempotentResponse(key string, now time.Time) (IdempotentResponse, bool, error)
+
+    // SaveIdempotentResponse records a response and purges the ones expired before now
+    SaveIdempotentResponse(key string, response IdempotentResponse, now time.Time) error
+}
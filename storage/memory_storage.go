@@ -1,58 +1,239 @@
-package storage
-
-import (
-    "sync"
-    "string-analyzer/models"
-)
-
-var (
-    stringsMap = make(map[string]models.AnalysisResult)
-    mutex      = &sync.RWMutex{}
-)
-
-// StoreString saves an analyzed string
-func StoreString(result models.AnalysisResult) {
-    mutex.Lock()
-    defer mutex.Unlock()
-    stringsMap[result.Value] = result
-}
-
-// GetString retrieves a string by its value
-func GetString(value string) (models.AnalysisResult, bool) {
-    mutex.RLock()
-    defer mutex.RUnlock()
-    result, exists := stringsMap[value]
-    return result, exists
-}
-
-// GetAllStrings returns all stored strings
-func GetAllStrings() []models.AnalysisResult {
-    mutex.RLock()
-    defer mutex.RUnlock()
-    
-    results := make([]models.AnalysisResult, 0, len(stringsMap))
-    for _, result := range stringsMap {
-        results = append(results, result)
-    }
-    return results
-}
-
-// DeleteString removes a string by its value
-func DeleteString(value string) bool {
-    mutex.Lock()
-    defer mutex.Unlock()
-    
-    if _, exists := stringsMap[value]; exists {
-        delete(stringsMap, value)
-        return true
-    }
-    return false
-}
-
-// StringExists checks if a string already exists
-func StringExists(value string) bool {
-    mutex.RLock()
-    defer mutex.RUnlock()
-    _, exists := stringsMap[value]
-    return exists
-}
\ No newline at end of file
+package storage
+
+import (
+    "sort"
+    "sync"
+    "time"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// MemoryStorage keeps analyzed strings in a map. It is meant for tests and
+// throwaway environments; nothing survives a restart.
+type MemoryStorage struct {
+    mutex      sync.RWMutex
+    stringsMap map[string]models.AnalysisResult
+    responses  map[string]IdempotentResponse
+}
+
+// NewMemoryStorage returns an empty in-memory repository
+func NewMemoryStorage() *MemoryStorage {
+    return &MemoryStorage{
+        stringsMap: make(map[string]models.AnalysisResult),
+        responses:  make(map[string]IdempotentResponse),
+    }
+}
+
+// StoreString saves an analyzed string
+func (s *MemoryStorage) StoreString(result models.AnalysisResult) error {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    s.stringsMap[result.Value] = result
+    return nil
+}
+
+// CreateOrGetString stores result unless its value is already stored
+func (s *MemoryStorage) CreateOrGetString(result models.AnalysisResult) (models.AnalysisResult, bool, error) {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    if existing, exists := s.stringsMap[result.Value]; exists {
+        return existing, false, nil
+    }
+    s.stringsMap[result.Value] = result
+    return result, true, nil
+}
+
+// UpsertString stores result, keeping the ID and created_at of a stored value
+func (s *MemoryStorage) UpsertString(result models.AnalysisResult) (models.AnalysisResult, bool, error) {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    existing, exists := s.stringsMap[result.Value]
+    if exists {
+        result.ID = existing.ID
+        result.CreatedAt = existing.CreatedAt
+    }
+    s.stringsMap[result.Value] = result
+    return result, !exists, nil
+}
+
+// UpsertStrings upserts every result in order
+func (s *MemoryStorage) UpsertStrings(results []models.AnalysisResult) ([]bool, error) {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    created := make([]bool, len(results))
+    for i, result := range results {
+        existing, exists := s.stringsMap[result.Value]
+        if exists {
+            result.ID = existing.ID
+            result.CreatedAt = existing.CreatedAt
+        }
+        s.stringsMap[result.Value] = result
+        created[i] = !exists
+    }
+    return created, nil
+}
+
+// StoreStrings saves the results whose values are not stored yet
+func (s *MemoryStorage) StoreStrings(results []models.AnalysisResult) ([]bool, error) {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    inserted := make([]bool, len(results))
+    for i, result := range results {
+        if _, exists := s.stringsMap[result.Value]; !exists {
+            s.stringsMap[result.Value] = result
+            inserted[i] = true
+        }
+    }
+    return inserted, nil
+}
+
+// GetString retrieves a string by its value
+func (s *MemoryStorage) GetString(value string) (models.AnalysisResult, bool, error) {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    result, exists := s.stringsMap[value]
+    return result, exists, nil
+}
+
+// GetStringByID retrieves a string by its ID
+func (s *MemoryStorage) GetStringByID(id string) (models.AnalysisResult, bool, error) {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    for _, result := range s.stringsMap {
+        if result.ID == id {
+            return result, true, nil
+        }
+    }
+    return models.AnalysisResult{}, false, nil
+}
+
+// ListStrings returns one page of stored strings matching the query
+func (s *MemoryStorage) ListStrings(query Query) (Page, error) {
+    cursor, hasCursor, err := query.StartAfter()
+    if err != nil {
+        return Page{}, err
+    }
+    
+    s.mutex.RLock()
+    results := make([]models.AnalysisResult, 0)
+    for _, result := range s.stringsMap {
+        if hasCursor && !query.Sort.After(result, cursor) {
+            continue
+        }
+        if query.Filters.Matches(result) {
+            results = append(results, result)
+        }
+    }
+    s.mutex.RUnlock()
+    
+    sort.Slice(results, func(i, j int) bool { return query.Sort.Compare(results[i], results[j]) < 0 })
+    
+    page := Page{Results: results}
+    if limit := query.PageSize(); len(results) > limit {
+        page.Results = results[:limit]
+        page.NextCursor = EncodeCursor(query.Sort.CursorFor(results[limit-1]))
+    }
+    return page, nil
+}
+
+// EachString calls fn for every matching string in created_at order
+func (s *MemoryStorage) EachString(filters Filters, fn func(models.AnalysisResult) error) error {
+    s.mutex.RLock()
+    results := make([]models.AnalysisResult, 0)
+    for _, result := range s.stringsMap {
+        if filters.Matches(result) {
+            results = append(results, result)
+        }
+    }
+    s.mutex.RUnlock()
+    
+    sort.Slice(results, func(i, j int) bool {
+        if results[i].CreatedAt != results[j].CreatedAt {
+            return results[i].CreatedAt < results[j].CreatedAt
+        }
+        return results[i].ID < results[j].ID
+    })
+    for _, result := range results {
+        if err := fn(result); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// FindByHash returns the strings whose digest under the algorithm matches
+func (s *MemoryStorage) FindByHash(algorithm, digest string) ([]models.AnalysisResult, error) {
+    s.mutex.RLock()
+    results := make([]models.AnalysisResult, 0)
+    for _, result := range s.stringsMap {
+        if digest != "" && result.Hashes[algorithm] == digest {
+            results = append(results, result)
+        }
+    }
+    s.mutex.RUnlock()
+    
+    sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+    return results, nil
+}
+
+// DeleteString removes a string by its value
+func (s *MemoryStorage) DeleteString(value string) (bool, error) {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+
+    if _, exists := s.stringsMap[value]; exists {
+        delete(s.stringsMap, value)
+        return true, nil
+    }
+    return false, nil
+}
+
+// DeleteStringByID removes a string by its ID
+func (s *MemoryStorage) DeleteStringByID(id string) (bool, error) {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    
+    for value, result := range s.stringsMap {
+        if result.ID == id {
+            delete(s.stringsMap, value)
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// StringExists checks if a string already exists
+func (s *MemoryStorage) StringExists(value string) (bool, error) {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    _, exists := s.stringsMap[value]
+    return exists, nil
+}
+
+// GetIdempotentResponse returns the unexpired response stored under key
+func (s *MemoryStorage) GetIdempotentResponse(key string, now time.Time) (IdempotentResponse, bool, error) {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    response, exists := s.responses[key]
+    if !exists || !response.ExpiresAt.After(now) {
+        return IdempotentResponse{}, false, nil
+    }
+    return response, true, nil
+}
+
+// SaveIdempotentResponse records a response and drops expired ones
+func (s *MemoryStorage) SaveIdempotentResponse(key string, response IdempotentResponse, now time.Time) error {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    for k, stored := range s.responses {
+        if !stored.ExpiresAt.After(now) {
+            delete(s.responses, k)
+        }
+    }
+    s.responses[key] = response
+    return nil
+}
+
+// Close is a no-op; there is nothing to release
+func (s *MemoryStorage) Close() error {
+    return nil
+}
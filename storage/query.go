@@ -0,0 +1,233 @@
+package storage
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "sort"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+const (
+    // DefaultLimit is the page size used when the client does not ask for one
+    DefaultLimit = 100
+    // MaxLimit caps the page size a client can request
+    MaxLimit = 1000
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Filters narrows down a listing. Nil or empty fields are not applied.
+type Filters struct {
+    IsPalindrome        *bool            `json:"is_palindrome,omitempty"`
+    MinLength           *int             `json:"min_length,omitempty"`
+    MaxLength           *int             `json:"max_length,omitempty"`
+    WordCount           *int             `json:"word_count,omitempty"`
+    ContainsCharacter   string           `json:"contains_character,omitempty"`
+    HasPalindromicWords *bool            `json:"has_palindromic_words,omitempty"`
+    PalindromicWord     string           `json:"palindromic_word,omitempty"`
+    MostCommonWord      string           `json:"most_common_word,omitempty"`
+    ContainsSecret      *bool            `json:"contains_secret,omitempty"`
+    Script              string           `json:"script,omitempty"`
+    Language            string           `json:"language,omitempty"`
+    HasEmoji            *bool            `json:"has_emoji,omitempty"`
+    MixedScript         *bool            `json:"mixed_script,omitempty"`
+    // PIITypes keeps strings in which every listed type of personal data was found
+    PIITypes            []string         `json:"pii_types,omitempty"`
+    ContainsWord        string           `json:"contains_word,omitempty"`
+    // Ranges holds min_/max_ bounds keyed by a name from RangeFields
+    Ranges map[string]Range `json:"ranges,omitempty"`
+}
+
+// Range is an inclusive bound on a numeric property; nil ends are open
+type Range struct {
+    Min *float64 `json:"min,omitempty"`
+    Max *float64 `json:"max,omitempty"`
+}
+
+// Contains reports whether v lies within the range
+func (r Range) Contains(v float64) bool {
+    return (r.Min == nil || v >= *r.Min) && (r.Max == nil || v <= *r.Max)
+}
+
+// rangeField maps a property that accepts min_/max_ filters onto its column
+type rangeField struct {
+    column string
+    value  func(result models.AnalysisResult) float64
+}
+
+// rangeFields is the allowlist of properties filterable with min_<name> and
+// max_<name>. length keeps its dedicated integer MinLength/MaxLength filters.
+var rangeFields = map[string]rangeField{
+    "longest_palindrome_length": {"longest_palindrome_length", func(r models.AnalysisResult) float64 { return float64(r.LongestPalindromeLength) }},
+    "distinct_palindromes":      {"distinct_palindromes", func(r models.AnalysisResult) float64 { return float64(r.DistinctPalindromes) }},
+    "hapax_legomena":            {"hapax_legomena", func(r models.AnalysisResult) float64 { return float64(r.HapaxLegomena) }},
+    "emoji_count":               {"emoji_count", func(r models.AnalysisResult) float64 { return float64(r.EmojiCount) }},
+    "language_confidence":       {"language_confidence", func(r models.AnalysisResult) float64 { return r.LanguageConfidence }},
+    "sentence_count":            {"sentence_count", func(r models.AnalysisResult) float64 { return float64(r.SentenceCount) }},
+    "syllable_count":            {"syllable_count", func(r models.AnalysisResult) float64 { return float64(r.SyllableCount) }},
+    "average_word_length":       {"average_word_length", func(r models.AnalysisResult) float64 { return r.AverageWordLength }},
+    "flesch_reading_ease":       {"flesch_reading_ease", func(r models.AnalysisResult) float64 { return r.FleschReadingEase }},
+    "flesch_kincaid_grade":      {"flesch_kincaid_grade", func(r models.AnalysisResult) float64 { return r.FleschKincaidGrade }},
+    "lexical_diversity":         {"lexical_diversity", func(r models.AnalysisResult) float64 { return r.LexicalDiversity }},
+    "shannon_entropy":           {"shannon_entropy", func(r models.AnalysisResult) float64 { return r.ShannonEntropy }},
+    "normalized_entropy":        {"normalized_entropy", func(r models.AnalysisResult) float64 { return r.NormalizedEntropy }},
+    "compression_ratio":         {"compression_ratio", func(r models.AnalysisResult) float64 { return r.CompressionRatio }},
+}
+
+// RangeFields lists the property names that accept min_/max_ filters
+func RangeFields() []string {
+    names := make([]string, 0, len(rangeFields))
+    for name := range rangeFields {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// RangeColumn returns the SQL column behind a range field
+func RangeColumn(name string) string {
+    return rangeFields[name].column
+}
+
+// Matches reports whether a result passes every filter. Backends that cannot
+// push filters down to a query engine use this to filter in Go.
+func (f Filters) Matches(result models.AnalysisResult) bool {
+    if f.IsPalindrome != nil && result.IsPalindrome != *f.IsPalindrome {
+        return false
+    }
+    if f.MinLength != nil && result.Length < *f.MinLength {
+        return false
+    }
+    if f.MaxLength != nil && result.Length > *f.MaxLength {
+        return false
+    }
+    if f.WordCount != nil && result.WordCount != *f.WordCount {
+        return false
+    }
+    if f.ContainsCharacter != "" && !strings.Contains(result.Value, f.ContainsCharacter) {
+        return false
+    }
+    if f.HasPalindromicWords != nil && (len(result.PalindromicWords) > 0) != *f.HasPalindromicWords {
+        return false
+    }
+    if f.PalindromicWord != "" && !containsString(result.PalindromicWords, f.PalindromicWord) {
+        return false
+    }
+    if f.MostCommonWord != "" && result.MostCommonWord != f.MostCommonWord {
+        return false
+    }
+    if f.ContainsWord != "" && result.WordFrequencyMap[f.ContainsWord] == 0 {
+        return false
+    }
+    if f.ContainsSecret != nil && result.ContainsSecret != *f.ContainsSecret {
+        return false
+    }
+    if f.Script != "" && result.ScriptCounts[f.Script] == 0 {
+        return false
+    }
+    if f.Language != "" && result.Language != f.Language {
+        return false
+    }
+    if f.HasEmoji != nil && (result.EmojiCount > 0) != *f.HasEmoji {
+        return false
+    }
+    if f.MixedScript != nil && result.MixedScript != *f.MixedScript {
+        return false
+    }
+    for _, piiType := range f.PIITypes {
+        if !containsString(result.PIITypes, piiType) {
+            return false
+        }
+    }
+    for name, r := range f.Ranges {
+        if !r.Contains(rangeFields[name].value(result)) {
+            return false
+        }
+    }
+    return true
+}
+
+func containsString(list []string, s string) bool {
+    for _, item := range list {
+        if item == s {
+            return true
+        }
+    }
+    return false
+}
+
+// Query describes one page of a filtered listing
+type Query struct {
+    Filters Filters
+    // Sort orders the results; empty means by ID only
+    Sort Sort
+    // Limit is the maximum number of results; zero means DefaultLimit
+    Limit int
+    // Cursor is the opaque NextCursor of the previous page, empty for the first page
+    Cursor string
+}
+
+// PageSize returns the effective limit for the query
+func (q Query) PageSize() int {
+    if q.Limit <= 0 {
+        return DefaultLimit
+    }
+    if q.Limit > MaxLimit {
+        return MaxLimit
+    }
+    return q.Limit
+}
+
+// Page is one slice of a listing plus the cursor for the next one
+type Page struct {
+    Results []models.AnalysisResult
+    // NextCursor is empty when there are no more results
+    NextCursor string
+}
+
+// Cursor is the decoded position after which the next page starts: the
+// sort key values and ID of the last result, plus the sort spec they belong to.
+type Cursor struct {
+    Sort   string        `json:"s,omitempty"`
+    Values []interface{} `json:"v,omitempty"`
+    ID     string        `json:"id"`
+}
+
+// EncodeCursor turns a position into the opaque string handed to clients
+func EncodeCursor(cursor Cursor) string {
+    data, _ := json.Marshal(cursor)
+    return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor
+func DecodeCursor(encoded string) (Cursor, error) {
+    var cursor Cursor
+    data, err := base64.RawURLEncoding.DecodeString(encoded)
+    if err != nil {
+        return cursor, ErrInvalidCursor
+    }
+    if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+        return cursor, ErrInvalidCursor
+    }
+    return cursor, nil
+}
+
+// StartAfter decodes the query cursor. The bool is false on the first page.
+// A cursor issued for a different sort spec, or whose values do not have
+// the types of its sort fields, is rejected as invalid.
+func (q Query) StartAfter() (Cursor, bool, error) {
+    if q.Cursor == "" {
+        return Cursor{}, false, nil
+    }
+    cursor, err := DecodeCursor(q.Cursor)
+    if err != nil {
+        return cursor, false, err
+    }
+    if cursor.Sort != q.Sort.String() || len(cursor.Values) != len(q.Sort) || !q.Sort.validKey(cursor.Values) {
+        return cursor, false, ErrInvalidCursor
+    }
+    return cursor, true, nil
+}
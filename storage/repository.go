@@ -0,0 +1,61 @@
+package storage
+
+import (
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// StringRepository is the persistence contract the HTTP handlers depend on.
+// Both the SQLite store in the database package and MemoryStorage implement it.
+type StringRepository interface {
+    IdempotencyStore
+
+    // StoreString saves an analyzed string
+    StoreString(result models.AnalysisResult) error
+
+    // CreateOrGetString stores result unless its value is already stored, in
+    // which case the stored string is returned instead; the bool reports
+    // whether result was created
+    CreateOrGetString(result models.AnalysisResult) (models.AnalysisResult, bool, error)
+
+    // UpsertString stores result, replacing the analysis of an already stored
+    // value but keeping its ID and created_at; the bool reports whether it was created
+    UpsertString(result models.AnalysisResult) (models.AnalysisResult, bool, error)
+
+    // UpsertStrings upserts several analyzed strings in one go; created[i]
+    // reports whether results[i] was new rather than an update
+    UpsertStrings(results []models.AnalysisResult) (created []bool, err error)
+
+    // StoreStrings saves several analyzed strings at once, skipping values
+    // that are already stored; inserted[i] reports whether results[i] was saved
+    StoreStrings(results []models.AnalysisResult) (inserted []bool, err error)
+
+    // GetString retrieves a string by its value; the bool reports whether it was found
+    GetString(value string) (models.AnalysisResult, bool, error)
+
+    // GetStringByID retrieves a string by its ID; the bool reports whether it was found
+    GetStringByID(id string) (models.AnalysisResult, bool, error)
+
+    // ListStrings returns one page of stored strings matching the query
+    ListStrings(query Query) (Page, error)
+
+    // EachString calls fn for every stored string matching filters, in
+    // created_at order, reading from a cursor rather than loading them all;
+    // an error from fn stops the iteration and is returned
+    EachString(filters Filters, fn func(models.AnalysisResult) error) error
+
+    // FindByHash returns the strings whose digest under the algorithm matches,
+    // ordered by ID; checksums such as crc32 may match several
+    FindByHash(algorithm, digest string) ([]models.AnalysisResult, error)
+
+    // DeleteString removes a string by its value; the bool reports whether a row was removed
+    DeleteString(value string) (bool, error)
+
+    // DeleteStringByID removes a string by its ID; the bool reports whether a row was removed
+    DeleteStringByID(id string) (bool, error)
+
+    // StringExists checks if a string already exists
+    StringExists(value string) (bool, error)
+
+    // Close releases the backend; the repository must not be used afterwards
+    Close() error
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// sortableField maps a public property name onto its column and its value
+type sortableField struct {
+    column string
+    value  func(result models.AnalysisResult) interface{}
+}
+
+// sortableFields is the allowlist of properties clients may sort by. Only
+// numeric and time properties are listed; created_at is RFC 3339 UTC so it
+// orders correctly as text.
+var sortableFields = map[string]sortableField{
+    "length":            {"length", func(r models.AnalysisResult) interface{} { return r.Length }},
+    "byte_length":       {"byte_length", func(r models.AnalysisResult) interface{} { return r.ByteLength }},
+    "rune_count":        {"rune_count", func(r models.AnalysisResult) interface{} { return r.RuneCount }},
+    "grapheme_count":    {"grapheme_count", func(r models.AnalysisResult) interface{} { return r.GraphemeCount }},
+    "longest_palindrome_length": {"longest_palindrome_length", func(r models.AnalysisResult) interface{} { return r.LongestPalindromeLength }},
+    "distinct_palindromes":      {"distinct_palindromes", func(r models.AnalysisResult) interface{} { return r.DistinctPalindromes }},
+    "hapax_legomena":            {"hapax_legomena", func(r models.AnalysisResult) interface{} { return r.HapaxLegomena }},
+    "sentence_count":            {"sentence_count", func(r models.AnalysisResult) interface{} { return r.SentenceCount }},
+    "syllable_count":            {"syllable_count", func(r models.AnalysisResult) interface{} { return r.SyllableCount }},
+    "average_word_length":       {"average_word_length", func(r models.AnalysisResult) interface{} { return r.AverageWordLength }},
+    "flesch_reading_ease":       {"flesch_reading_ease", func(r models.AnalysisResult) interface{} { return r.FleschReadingEase }},
+    "flesch_kincaid_grade":      {"flesch_kincaid_grade", func(r models.AnalysisResult) interface{} { return r.FleschKincaidGrade }},
+    "lexical_diversity":         {"lexical_diversity", func(r models.AnalysisResult) interface{} { return r.LexicalDiversity }},
+    "shannon_entropy":           {"shannon_entropy", func(r models.AnalysisResult) interface{} { return r.ShannonEntropy }},
+    "normalized_entropy":        {"normalized_entropy", func(r models.AnalysisResult) interface{} { return r.NormalizedEntropy }},
+    "compression_ratio":         {"compression_ratio", func(r models.AnalysisResult) interface{} { return r.CompressionRatio }},
+    "emoji_count":               {"emoji_count", func(r models.AnalysisResult) interface{} { return r.EmojiCount }},
+    "language_confidence":       {"language_confidence", func(r models.AnalysisResult) interface{} { return r.LanguageConfidence }},
+    "unique_characters": {"unique_characters", func(r models.AnalysisResult) interface{} { return r.UniqueCharacters }},
+    "word_count":        {"word_count", func(r models.AnalysisResult) interface{} { return r.WordCount }},
+    "created_at":        {"created_at", func(r models.AnalysisResult) interface{} { return r.CreatedAt }},
+}
+
+// SortableFields lists the property names accepted by ParseSort
+func SortableFields() []string {
+    names := make([]string, 0, len(sortableFields))
+    for name := range sortableFields {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// SortField is one key of a sort specification
+type SortField struct {
+    Field string
+    Desc  bool
+}
+
+// Column returns the SQL column backing the field
+func (f SortField) Column() string {
+    return sortableFields[f.Field].column
+}
+
+// Sort is an ordered sort specification. Results are always ordered by ID
+// after the listed fields so that pagination is stable.
+type Sort []SortField
+
+// ParseSort parses a spec such as "-length,created_at". A leading '-' sorts
+// descending. Unknown or repeated fields are rejected.
+func ParseSort(spec string) (Sort, error) {
+    var result Sort
+    seen := make(map[string]bool)
+    for _, part := range strings.Split(spec, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+        field := SortField{Field: part}
+        if strings.HasPrefix(part, "-") {
+            field = SortField{Field: part[1:], Desc: true}
+        } else if strings.HasPrefix(part, "+") {
+            field.Field = part[1:]
+        }
+        if _, ok := sortableFields[field.Field]; !ok {
+            return nil, fmt.Errorf("unknown sort field '%s' (allowed: %s)", field.Field, strings.Join(SortableFields(), ", "))
+        }
+        if seen[field.Field] {
+            return nil, fmt.Errorf("sort field '%s' given more than once", field.Field)
+        }
+        seen[field.Field] = true
+        result = append(result, field)
+    }
+    return result, nil
+}
+
+// String renders the spec in the same form ParseSort accepts
+func (s Sort) String() string {
+    parts := make([]string, len(s))
+    for i, field := range s {
+        if field.Desc {
+            parts[i] = "-" + field.Field
+        } else {
+            parts[i] = field.Field
+        }
+    }
+    return strings.Join(parts, ",")
+}
+
+// Key returns the values of the sort fields for a result, as stored in cursors
+func (s Sort) Key(result models.AnalysisResult) []interface{} {
+    key := make([]interface{}, len(s))
+    for i, field := range s {
+        key[i] = sortableFields[field.Field].value(result)
+    }
+    return key
+}
+
+// validKey reports whether decoded cursor values have the types of the sort
+// fields: strings for text fields and JSON numbers for the rest
+func (s Sort) validKey(values []interface{}) bool {
+    for i, field := range s {
+        _, text := sortableFields[field.Field].value(models.AnalysisResult{}).(string)
+        switch values[i].(type) {
+        case string:
+            if !text {
+                return false
+            }
+        case float64:
+            if text {
+                return false
+            }
+        default:
+            return false
+        }
+    }
+    return true
+}
+
+// CursorFor builds the cursor that resumes after the given result
+func (s Sort) CursorFor(result models.AnalysisResult) Cursor {
+    return Cursor{Sort: s.String(), Values: s.Key(result), ID: result.ID}
+}
+
+// Compare orders two results by the spec, falling back to ID
+func (s Sort) Compare(a, b models.AnalysisResult) int {
+    return s.compareKeys(s.Key(a), a.ID, s.Key(b), b.ID)
+}
+
+// After reports whether a result sorts strictly after the cursor position
+func (s Sort) After(result models.AnalysisResult, cursor Cursor) bool {
+    return s.compareKeys(s.Key(result), result.ID, cursor.Values, cursor.ID) > 0
+}
+
+func (s Sort) compareKeys(a []interface{}, aID string, b []interface{}, bID string) int {
+    for i, field := range s {
+        cmp := compareValues(a[i], b[i])
+        if field.Desc {
+            cmp = -cmp
+        }
+        if cmp != 0 {
+            return cmp
+        }
+    }
+    return strings.Compare(aID, bID)
+}
+
+// compareValues compares two sort values. Numbers may arrive as int or as
+// float64 after a trip through a JSON cursor.
+func compareValues(a, b interface{}) int {
+    if as, ok := a.(string); ok {
+        bs, _ := b.(string)
+        return strings.Compare(as, bs)
+    }
+    af, bf := toFloat(a), toFloat(b)
+    switch {
+    case af < bf:
+        return -1
+    case af > bf:
+        return 1
+    }
+    return 0
+}
+
+func toFloat(v interface{}) float64 {
+    switch n := v.(type) {
+    case int:
+        return float64(n)
+    case int64:
+        return float64(n)
+    case float64:
+        return n
+    }
+    return 0
+}
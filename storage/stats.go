@@ -0,0 +1,70 @@
+package storage
+
+import (
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// undeterminedLanguage counts strings whose language was not detected
+const undeterminedLanguage = "und"
+
+// Stats summarizes a set of stored strings
+type Stats struct {
+    Count         int     `json:"count"`
+    Palindromes   int     `json:"palindromes"`
+    WithSecrets   int     `json:"with_secrets"`
+    WithPII       int     `json:"with_pii"`
+    MinLength     int     `json:"min_length"`
+    MaxLength     int     `json:"max_length"`
+    AverageLength float64 `json:"average_length"`
+    // Languages counts strings per ISO 639-1 code, "und" when undetermined
+    Languages map[string]int `json:"languages"`
+    // Scripts counts the strings containing each Unicode script
+    Scripts map[string]int `json:"scripts"`
+    // OldestCreatedAt and NewestCreatedAt are empty when nothing matched
+    OldestCreatedAt string `json:"oldest_created_at"`
+    NewestCreatedAt string `json:"newest_created_at"`
+}
+
+// CollectStats summarizes the strings matching filters in a single pass
+// over EachString, so it costs a full scan but little memory
+func CollectStats(repo StringRepository, filters Filters) (Stats, error) {
+    stats := Stats{Languages: map[string]int{}, Scripts: map[string]int{}}
+    totalLength := 0
+    err := repo.EachString(filters, func(result models.AnalysisResult) error {
+        if stats.Count == 0 || result.Length < stats.MinLength {
+            stats.MinLength = result.Length
+        }
+        if result.Length > stats.MaxLength {
+            stats.MaxLength = result.Length
+        }
+        if stats.Count == 0 {
+            stats.OldestCreatedAt = result.CreatedAt
+        }
+        stats.NewestCreatedAt = result.CreatedAt
+        stats.Count++
+        totalLength += result.Length
+        
+        if result.IsPalindrome {
+            stats.Palindromes++
+        }
+        if result.ContainsSecret {
+            stats.WithSecrets++
+        }
+        if len(result.PIITypes) > 0 {
+            stats.WithPII++
+        }
+        language := result.Language
+        if language == "" {
+            language = undeterminedLanguage
+        }
+        stats.Languages[language]++
+        for script := range result.ScriptCounts {
+            stats.Scripts[script]++
+        }
+        return nil
+    })
+    if stats.Count > 0 {
+        stats.AverageLength = float64(totalLength) / float64(stats.Count)
+    }
+    return stats, err
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+    "testing"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// TestCollectStats tests the totals and that filters narrow them
+func TestCollectStats(t *testing.T) {
+    repo := NewMemoryStorage()
+    for _, result := range []models.AnalysisResult{
+        {ID: "1", Value: "level", Length: 5, IsPalindrome: true, Language: "en", ScriptCounts: map[string]int{"Latin": 5}, CreatedAt: "2024-01-01T00:00:00Z"},
+        {ID: "2", Value: "мир 42", Length: 6, ContainsSecret: true, ScriptCounts: map[string]int{"Cyrillic": 3, "Common": 3}, CreatedAt: "2024-01-02T00:00:00Z"},
+        {ID: "3", Value: "a@b.io", Length: 6, PIITypes: []string{"email"}, Language: "en", ScriptCounts: map[string]int{"Latin": 4, "Common": 2}, CreatedAt: "2024-01-03T00:00:00Z"},
+    } {
+        repo.StoreString(result)
+    }
+    
+    stats, err := CollectStats(repo, Filters{})
+    if err != nil {
+        t.Fatalf("CollectStats failed: %v", err)
+    }
+    if stats.Count != 3 || stats.Palindromes != 1 || stats.WithSecrets != 1 || stats.WithPII != 1 {
+        t.Errorf("Unexpected counts %+v", stats)
+    }
+    if stats.MinLength != 5 || stats.MaxLength != 6 || stats.AverageLength != 17.0/3 {
+        t.Errorf("Unexpected lengths %+v", stats)
+    }
+    if stats.Languages["en"] != 2 || stats.Languages["und"] != 1 || stats.Scripts["Latin"] != 2 || stats.Scripts["Common"] != 2 {
+        t.Errorf("Unexpected breakdowns %+v", stats)
+    }
+    if stats.OldestCreatedAt != "2024-01-01T00:00:00Z" || stats.NewestCreatedAt != "2024-01-03T00:00:00Z" {
+        t.Errorf("Unexpected time range %+v", stats)
+    }
+    
+    palindromes := true
+    stats, err = CollectStats(repo, Filters{IsPalindrome: &palindromes})
+    if err != nil || stats.Count != 1 || stats.MinLength != 5 {
+        t.Errorf("Unexpected filtered stats %+v, %v", stats, err)
+    }
+    
+    stats, err = CollectStats(NewMemoryStorage(), Filters{})
+    if err != nil || stats.Count != 0 || stats.AverageLength != 0 || stats.OldestCreatedAt != "" {
+        t.Errorf("Unexpected empty stats %+v, %v", stats, err)
+    }
+}
@@ -0,0 +1,89 @@
+package transfer
+
+import (
+    "encoding/json"
+    "reflect"
+    "strconv"
+    "strings"
+    "github.com/holladworld/string-analyzer/models"
+)
+
+// Kind is how a column is represented in tabular formats
+type Kind int
+
+const (
+    KindString Kind = iota
+    KindInt
+    KindFloat
+    KindBool
+    // KindJSON holds maps and lists as JSON text, e.g. a
+    // character_frequency_map of {"a":2,"b":1}; map keys are sorted
+    KindJSON
+)
+
+// Column is one field of models.AnalysisResult in CSV and Parquet exports
+type Column struct {
+    Name  string
+    Kind  Kind
+    index int
+}
+
+// Columns lists the tabular columns in export order. They are derived from
+// the JSON tags of models.AnalysisResult, so new properties are exported
+// without changes here.
+var Columns = tableColumns()
+
+func tableColumns() []Column {
+    t := reflect.TypeOf(models.AnalysisResult{})
+    columns := make([]Column, 0, t.NumField())
+    for i := 0; i < t.NumField(); i++ {
+        name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+        if name == "" || name == "-" {
+            continue
+        }
+        kind := KindJSON
+        switch t.Field(i).Type.Kind() {
+        case reflect.String:
+            kind = KindString
+        case reflect.Int, reflect.Int64:
+            kind = KindInt
+        case reflect.Float64:
+            kind = KindFloat
+        case reflect.Bool:
+            kind = KindBool
+        }
+        columns = append(columns, Column{Name: name, Kind: kind, index: i})
+    }
+    return columns
+}
+
+// field returns the column's field of result
+func (c Column) field(result *models.AnalysisResult) reflect.Value {
+    return reflect.ValueOf(result).Elem().Field(c.index)
+}
+
+// isNull reports a nil map or list, exported as an empty cell
+func (c Column) isNull(result *models.AnalysisResult) bool {
+    v := c.field(result)
+    return c.Kind == KindJSON && v.IsNil()
+}
+
+// Text renders the column as it appears in CSV
+func (c Column) Text(result *models.AnalysisResult) (string, error) {
+    v := c.field(result)
+    switch c.Kind {
+    case KindInt:
+        return strconv.FormatInt(v.Int(), 10), nil
+    case KindFloat:
+        return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+    case KindBool:
+        return strconv.FormatBool(v.Bool()), nil
+    case KindJSON:
+        if c.isNull(result) {
+            return "", nil
+        }
+        data, err := json.Marshal(v.Interface())
+        return string(data), err
+    }
+    return v.String(), nil
+}
@@ -0,0 +1,211 @@
+package transfer
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/parquet-go/parquet-go"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+    // FormatJSONL writes one AnalysisResult per line, as GET /strings returns it
+    FormatJSONL Format = "jsonl"
+    // FormatCSV writes a header row and one row per string, see Columns
+    FormatCSV Format = "csv"
+    // FormatParquet writes a Snappy-compressed Parquet file with the CSV columns
+    FormatParquet Format = "parquet"
+)
+
+// parquetRowGroupSize bounds how many rows are buffered before a row group
+// is written out
+const parquetRowGroupSize = 10000
+
+// Formats lists the supported export formats
+func Formats() []string {
+    return []string{string(FormatJSONL), string(FormatCSV), string(FormatParquet)}
+}
+
+// ParseFormat validates a format name; the empty string selects JSON Lines
+func ParseFormat(name string) (Format, error) {
+    switch Format(name) {
+    case "":
+        return FormatJSONL, nil
+    case FormatJSONL, FormatCSV, FormatParquet:
+        return Format(name), nil
+    }
+    return "", fmt.Errorf("unknown format '%s'", name)
+}
+
+// ContentType is the media type of the format
+func (f Format) ContentType() string {
+    switch f {
+    case FormatCSV:
+        return "text/csv; charset=utf-8"
+    case FormatParquet:
+        return "application/vnd.apache.parquet"
+    }
+    return "application/x-ndjson"
+}
+
+// Encoder writes analysis results in one format. Close finishes the output
+// but does not close the underlying writer.
+type Encoder interface {
+    Encode(result models.AnalysisResult) error
+    Close() error
+}
+
+// NewEncoder returns an encoder writing format to w
+func NewEncoder(format Format, w io.Writer) Encoder {
+    switch format {
+    case FormatCSV:
+        return &csvEncoder{writer: csv.NewWriter(w)}
+    case FormatParquet:
+        return newParquetEncoder(w)
+    }
+    encoder := json.NewEncoder(w)
+    encoder.SetEscapeHTML(false)
+    return jsonlEncoder{encoder}
+}
+
+type jsonlEncoder struct {
+    encoder *json.Encoder
+}
+
+func (e jsonlEncoder) Encode(result models.AnalysisResult) error {
+    return e.encoder.Encode(result)
+}
+
+func (e jsonlEncoder) Close() error {
+    return nil
+}
+
+type csvEncoder struct {
+    writer      *csv.Writer
+    wroteHeader bool
+}
+
+func (e *csvEncoder) Encode(result models.AnalysisResult) error {
+    if !e.wroteHeader {
+        if err := e.writer.Write(header()); err != nil {
+            return err
+        }
+        e.wroteHeader = true
+    }
+    record := make([]string, len(Columns))
+    for i, c := range Columns {
+        text, err := c.Text(&result)
+        if err != nil {
+            return err
+        }
+        record[i] = text
+    }
+    return e.writer.Write(record)
+}
+
+// Close writes the header for an empty export and flushes
+func (e *csvEncoder) Close() error {
+    if !e.wroteHeader {
+        if err := e.writer.Write(header()); err != nil {
+            return err
+        }
+    }
+    e.writer.Flush()
+    return e.writer.Error()
+}
+
+func header() []string {
+    names := make([]string, len(Columns))
+    for i, c := range Columns {
+        names[i] = c.Name
+    }
+    return names
+}
+
+type parquetEncoder struct {
+    writer *parquet.Writer
+    // position maps each of Columns to its leaf index in the schema, which
+    // orders a group's fields by name
+    position []int
+    row      parquet.Row
+    buffered int
+}
+
+func newParquetEncoder(w io.Writer) *parquetEncoder {
+    group := parquet.Group{}
+    for _, c := range Columns {
+        switch c.Kind {
+        case KindInt:
+            group[c.Name] = parquet.Int(64)
+        case KindFloat:
+            group[c.Name] = parquet.Leaf(parquet.DoubleType)
+        case KindBool:
+            group[c.Name] = parquet.Leaf(parquet.BooleanType)
+        case KindJSON:
+            group[c.Name] = parquet.Optional(parquet.JSON())
+        default:
+            group[c.Name] = parquet.String()
+        }
+    }
+    schema := parquet.NewSchema("analyzed_strings", group)
+    
+    leaves := map[string]int{}
+    for i, path := range schema.Columns() {
+        leaves[path[0]] = i
+    }
+    position := make([]int, len(Columns))
+    for i, c := range Columns {
+        position[i] = leaves[c.Name]
+    }
+    
+    return &parquetEncoder{
+        writer:   parquet.NewWriter(w, schema, parquet.Compression(&parquet.Snappy)),
+        position: position,
+        row:      make(parquet.Row, len(Columns)),
+    }
+}
+
+func (e *parquetEncoder) Encode(result models.AnalysisResult) error {
+    for i, c := range Columns {
+        var value parquet.Value
+        field := c.field(&result)
+        switch c.Kind {
+        case KindInt:
+            value = parquet.Int64Value(field.Int())
+        case KindFloat:
+            value = parquet.DoubleValue(field.Float())
+        case KindBool:
+            value = parquet.BooleanValue(field.Bool())
+        case KindJSON:
+            if c.isNull(&result) {
+                e.row[e.position[i]] = parquet.Value{}.Level(0, 0, e.position[i])
+                continue
+            }
+            data, err := json.Marshal(field.Interface())
+            if err != nil {
+                return err
+            }
+            e.row[e.position[i]] = parquet.ByteArrayValue(data).Level(0, 1, e.position[i])
+            continue
+        default:
+            value = parquet.ByteArrayValue([]byte(field.String()))
+        }
+        e.row[e.position[i]] = value.Level(0, 0, e.position[i])
+    }
+    if _, err := e.writer.WriteRows([]parquet.Row{e.row}); err != nil {
+        return err
+    }
+    if e.buffered++; e.buffered == parquetRowGroupSize {
+        e.buffered = 0
+        return e.writer.Flush()
+    }
+    return nil
+}
+
+func (e *parquetEncoder) Close() error {
+    return e.writer.Close()
+}
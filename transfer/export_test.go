@@ -0,0 +1,106 @@
+package transfer
+
+import (
+    "bytes"
+    "encoding/csv"
+    "encoding/json"
+    "io"
+    "slices"
+    "testing"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/parquet-go/parquet-go"
+)
+
+func encodeAll(t *testing.T, format Format, results ...models.AnalysisResult) []byte {
+    t.Helper()
+    var buf bytes.Buffer
+    encoder := NewEncoder(format, &buf)
+    for _, result := range results {
+        if err := encoder.Encode(result); err != nil {
+            t.Fatalf("Encode failed: %v", err)
+        }
+    }
+    if err := encoder.Close(); err != nil {
+        t.Fatalf("Close failed: %v", err)
+    }
+    return buf.Bytes()
+}
+
+// TestJSONL tests one AnalysisResult per line
+func TestJSONL(t *testing.T) {
+    data := encodeAll(t, FormatJSONL, services.AnalyzeString("a<b"), services.AnalyzeString("level"))
+    lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+    if len(lines) != 2 {
+        t.Fatalf("Expected 2 lines, got %q", data)
+    }
+    var result models.AnalysisResult
+    if err := json.Unmarshal(lines[0], &result); err != nil || result.Value != "a<b" {
+        t.Errorf("Line did not round-trip: %s (%v)", lines[0], err)
+    }
+}
+
+// TestCSV tests the header and the flattening of maps into JSON cells
+func TestCSV(t *testing.T) {
+    data := encodeAll(t, FormatCSV, services.AnalyzeString("hello, world"))
+    records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+    if err != nil || len(records) != 2 {
+        t.Fatalf("Expected a header and one row, got %v, %v", records, err)
+    }
+    row := map[string]string{}
+    for i, name := range records[0] {
+        row[name] = records[1][i]
+    }
+    if row["value"] != "hello, world" || row["length"] != "12" || row["is_palindrome"] != "false" {
+        t.Errorf("Unexpected scalar cells %v", row)
+    }
+    var frequencies map[string]int
+    if err := json.Unmarshal([]byte(row["character_frequency_map"]), &frequencies); err != nil || frequencies["l"] != 3 {
+        t.Errorf("Expected character_frequency_map as JSON, got %q", row["character_frequency_map"])
+    }
+    
+    empty, _ := csv.NewReader(bytes.NewReader(encodeAll(t, FormatCSV))).ReadAll()
+    if len(empty) != 1 || !slices.Contains(empty[0], "custom_properties") {
+        t.Errorf("Expected only the header for an empty export, got %v", empty)
+    }
+}
+
+// TestParquet tests that the file reads back with typed columns
+func TestParquet(t *testing.T) {
+    type row struct {
+        Value                 string  `parquet:"value"`
+        Length                int64   `parquet:"length"`
+        IsPalindrome          bool    `parquet:"is_palindrome"`
+        ShannonEntropy        float64 `parquet:"shannon_entropy"`
+        CharacterFrequencyMap *string `parquet:"character_frequency_map,optional"`
+        CustomProperties      *string `parquet:"custom_properties,optional"`
+    }
+    data := encodeAll(t, FormatParquet, services.AnalyzeString("level"), services.AnalyzeString("hello"))
+    
+    reader := parquet.NewGenericReader[row](bytes.NewReader(data))
+    defer reader.Close()
+    rows := make([]row, 2)
+    n, err := reader.Read(rows)
+    if n != 2 || (err != nil && err != io.EOF) {
+        t.Fatalf("Read %d rows: %v", n, err)
+    }
+    if rows[0].Value != "level" || rows[0].Length != 5 || !rows[0].IsPalindrome || rows[1].ShannonEntropy == 0 {
+        t.Errorf("Unexpected rows %+v", rows)
+    }
+    if rows[0].CharacterFrequencyMap == nil || *rows[0].CharacterFrequencyMap != `{"e":2,"l":2,"v":1}` {
+        t.Errorf("Unexpected character_frequency_map %q", *rows[0].CharacterFrequencyMap)
+    }
+    if rows[0].CustomProperties != nil {
+        t.Errorf("Expected a null custom_properties, got %q", *rows[0].CustomProperties)
+    }
+}
+
+// TestParseFormat tests the default and unknown names
+func TestParseFormat(t *testing.T) {
+    if format, err := ParseFormat(""); err != nil || format != FormatJSONL {
+        t.Errorf("Expected jsonl by default, got %q, %v", format, err)
+    }
+    if _, err := ParseFormat("xml"); err == nil {
+        t.Error("Expected xml to be refused")
+    }
+}
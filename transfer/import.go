@@ -0,0 +1,294 @@
+package transfer
+
+import (
+    "bufio"
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "strings"
+    "unicode/utf8"
+    "github.com/holladworld/string-analyzer/models"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/storage"
+)
+
+// ImportFormat is an import file format
+type ImportFormat string
+
+const (
+    // ImportText reads one value per line; blank lines are skipped
+    ImportText ImportFormat = "text"
+    // ImportCSV reads the values of one column, named in the header row
+    ImportCSV ImportFormat = "csv"
+    // ImportJSONL reads objects with a "value" field, one per line, as
+    // written by the jsonl export
+    ImportJSONL ImportFormat = "jsonl"
+)
+
+// ImportFormats lists the supported import formats
+func ImportFormats() []string {
+    return []string{string(ImportText), string(ImportCSV), string(ImportJSONL)}
+}
+
+// ParseImportFormat validates a format name; the empty string selects text
+func ParseImportFormat(name string) (ImportFormat, error) {
+    switch ImportFormat(name) {
+    case "":
+        return ImportText, nil
+    case ImportText, ImportCSV, ImportJSONL:
+        return ImportFormat(name), nil
+    case "ndjson":
+        return ImportJSONL, nil
+    }
+    return "", fmt.Errorf("unknown format '%s'", name)
+}
+
+// ImportFormatFor guesses the format from a content type or a file
+// extension, falling back to text
+func ImportFormatFor(hint string) ImportFormat {
+    switch strings.ToLower(hint) {
+    case "text/csv", "application/csv", ".csv":
+        return ImportCSV
+    case "application/x-ndjson", "application/ndjson", "application/jsonl", ".jsonl", ".ndjson":
+        return ImportJSONL
+    }
+    return ImportText
+}
+
+// DuplicatePolicy decides what happens to values that are already stored
+type DuplicatePolicy string
+
+const (
+    // DuplicateSkip leaves the stored analysis alone
+    DuplicateSkip DuplicatePolicy = "skip"
+    // DuplicateUpdate replaces it with a fresh analysis, as PUT /strings does
+    DuplicateUpdate DuplicatePolicy = "update"
+)
+
+// ParseDuplicatePolicy validates a policy name; the empty string selects skip
+func ParseDuplicatePolicy(name string) (DuplicatePolicy, error) {
+    switch DuplicatePolicy(name) {
+    case "":
+        return DuplicateSkip, nil
+    case DuplicateSkip, DuplicateUpdate:
+        return DuplicatePolicy(name), nil
+    }
+    return "", fmt.Errorf("unknown duplicate policy '%s' (allowed: skip, update)", name)
+}
+
+// DefaultImportColumn is the CSV column read when none is chosen
+const DefaultImportColumn = "value"
+
+// ImportOptions configures Import. The zero value reads text and skips duplicates.
+type ImportOptions struct {
+    Format ImportFormat
+    // Column names the CSV column holding the values
+    Column      string
+    OnDuplicate DuplicatePolicy
+    Analysis    services.Options
+    // SecretPolicy applies to values with secrets; rejected lines fail
+    SecretPolicy services.SecretPolicy
+}
+
+// LineError is a line that could not be imported
+type LineError struct {
+    Line  int    `json:"line"`
+    Error string `json:"error"`
+}
+
+// maxReportedErrors bounds ImportReport.Errors; Failed still counts every line
+const maxReportedErrors = 100
+
+// ImportReport summarizes an import
+type ImportReport struct {
+    Created int         `json:"created"`
+    Updated int         `json:"updated"`
+    Skipped int         `json:"skipped"`
+    Failed  int         `json:"failed"`
+    Errors  []LineError `json:"errors"`
+}
+
+func (r *ImportReport) fail(line int, err error) {
+    r.Failed++
+    if len(r.Errors) < maxReportedErrors {
+        r.Errors = append(r.Errors, LineError{Line: line, Error: err.Error()})
+    }
+}
+
+// importChunkSize is how many values are stored or upserted per transaction
+const importChunkSize = 500
+
+// Import reads values from r, analyzes them and stores them in repo. Bad
+// lines are counted in the report rather than stopping the import; an
+// error is returned only when the input cannot be read at all, a required
+// analyzer fails or storage fails, in which case the report covers what
+// was stored until then.
+func Import(repo storage.StringRepository, r io.Reader, opts ImportOptions) (ImportReport, error) {
+    report := ImportReport{Errors: []LineError{}}
+    var chunk []models.AnalysisResult
+    
+    flush := func() error {
+        if len(chunk) == 0 {
+            return nil
+        }
+        // Both return whether each value was new; what else happened depends on the policy
+        store, existing := repo.StoreStrings, &report.Skipped
+        if opts.OnDuplicate == DuplicateUpdate {
+            store, existing = repo.UpsertStrings, &report.Updated
+        }
+        created, err := store(chunk)
+        if err != nil {
+            return err
+        }
+        for _, isNew := range created {
+            if isNew {
+                report.Created++
+            } else {
+                *existing++
+            }
+        }
+        chunk = chunk[:0]
+        return nil
+    }
+    
+    err := readValues(r, opts, func(line int, value string, err error) error {
+        if err != nil {
+            report.fail(line, err)
+            return nil
+        }
+        result, rejected, err := services.AnalyzeWithPolicy(value, opts.Analysis, opts.SecretPolicy)
+        if err != nil {
+            return err
+        }
+        if rejected {
+            report.fail(line, errors.New("value appears to contain secrets"))
+            return nil
+        }
+        
+        chunk = append(chunk, result)
+        if len(chunk) == importChunkSize {
+            return flush()
+        }
+        return nil
+    })
+    if err != nil {
+        return report, err
+    }
+    return report, flush()
+}
+
+// readValues calls fn with each value and its 1-based line number, or with
+// the error that made the line unreadable. An error from fn stops reading.
+func readValues(r io.Reader, opts ImportOptions, fn func(line int, value string, err error) error) error {
+    switch opts.Format {
+    case ImportCSV:
+        return readCSVValues(r, opts.Column, fn)
+    case ImportJSONL:
+        return readLines(r, func(line int, text string) error {
+            var entry struct {
+                Value json.RawMessage `json:"value"`
+            }
+            if err := json.Unmarshal([]byte(text), &entry); err != nil {
+                return fn(line, "", errors.New("invalid JSON"))
+            }
+            var value string
+            if entry.Value == nil || string(entry.Value) == "null" {
+                return fn(line, "", errors.New("missing 'value' field"))
+            }
+            if err := json.Unmarshal(entry.Value, &value); err != nil {
+                return fn(line, "", errors.New("'value' must be a string"))
+            }
+            return fn(line, value, nil)
+        })
+    }
+    return readLines(r, func(line int, text string) error {
+        if !utf8.ValidString(text) {
+            return fn(line, "", errors.New("invalid UTF-8"))
+        }
+        return fn(line, text, nil)
+    })
+}
+
+// readLines calls fn for every non-blank line without its line ending
+func readLines(r io.Reader, fn func(line int, text string) error) error {
+    reader := bufio.NewReader(r)
+    for line := 1; ; line++ {
+        text, err := reader.ReadString('\n')
+        if err != nil && err != io.EOF {
+            return err
+        }
+        text = strings.TrimRight(text, "\r\n")
+        if strings.TrimSpace(text) != "" {
+            if err := fn(line, text); err != nil {
+                return err
+            }
+        }
+        if err == io.EOF {
+            return nil
+        }
+    }
+}
+
+// ErrMissingColumn is returned when the CSV header lacks the chosen column
+var ErrMissingColumn = errors.New("column not found in CSV header")
+
+func readCSVValues(r io.Reader, column string, fn func(line int, value string, err error) error) error {
+    if column == "" {
+        column = DefaultImportColumn
+    }
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = -1
+    
+    header, err := reader.Read()
+    if err == io.EOF {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    index := -1
+    for i, name := range header {
+        if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), column) {
+            index = i
+            break
+        }
+    }
+    if index < 0 {
+        return fmt.Errorf("%w: '%s'", ErrMissingColumn, column)
+    }
+    
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            return nil
+        }
+        var parseErr *csv.ParseError
+        if errors.As(err, &parseErr) {
+            if err := fn(parseErr.StartLine, "", parseErr.Err); err != nil {
+                return err
+            }
+            continue
+        }
+        if err != nil {
+            return err
+        }
+        line, _ := reader.FieldPos(0)
+        if index >= len(record) {
+            err = fmt.Errorf("missing column '%s'", column)
+        }
+        if err == nil && !utf8.ValidString(record[index]) {
+            err = errors.New("invalid UTF-8")
+        }
+        if err != nil {
+            if err := fn(line, "", err); err != nil {
+                return err
+            }
+            continue
+        }
+        if err := fn(line, record[index], nil); err != nil {
+            return err
+        }
+    }
+}
@@ -0,0 +1,85 @@
+package transfer
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+    "github.com/holladworld/string-analyzer/services"
+    "github.com/holladworld/string-analyzer/storage"
+)
+
+// TestImportFormats tests line numbers and per-line errors for each format
+func TestImportFormats(t *testing.T) {
+    cases := []struct {
+        name    string
+        opts    ImportOptions
+        input   string
+        created int
+        lines   []int
+    }{
+        {"text", ImportOptions{}, "alpha\n\n  \nbeta\r\nbad\xff\n", 2, []int{5}},
+        {"csv", ImportOptions{Format: ImportCSV, Column: "Text"}, "\ufeffid,text\n1,alpha\n2\n3,\"beta\nwith newline\"\n4,gamma\n", 3, []int{3}},
+        {"jsonl", ImportOptions{Format: ImportJSONL}, "{\"value\": \"alpha\"}\nnot json\n\n{\"value\": 5}\n{\"other\": 1}\n{\"value\": \"beta\"}\n", 2, []int{2, 4, 5}},
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            report, err := Import(storage.NewMemoryStorage(), strings.NewReader(tc.input), tc.opts)
+            if err != nil {
+                t.Fatalf("Import failed: %v", err)
+            }
+            var lines []int
+            for _, lineErr := range report.Errors {
+                lines = append(lines, lineErr.Line)
+            }
+            if report.Created != tc.created || report.Failed != len(tc.lines) || !reflect.DeepEqual(lines, tc.lines) {
+                t.Errorf("Unexpected report %+v", report)
+            }
+        })
+    }
+    
+    _, err := Import(storage.NewMemoryStorage(), strings.NewReader("id,text\n1,a\n"), ImportOptions{Format: ImportCSV})
+    if err == nil || !strings.Contains(err.Error(), "'value'") {
+        t.Errorf("Expected a missing column error, got %v", err)
+    }
+}
+
+// TestImportDuplicates tests the skip and update policies
+func TestImportDuplicates(t *testing.T) {
+    repo := storage.NewMemoryStorage()
+    strict := services.Options{PalindromeMode: services.PalindromeStrict}
+    report, err := Import(repo, strings.NewReader("Level\nLevel\nother\n"), ImportOptions{Analysis: strict})
+    if err != nil || report.Created != 2 || report.Skipped != 1 {
+        t.Fatalf("Unexpected first import %+v, %v", report, err)
+    }
+    
+    report, err = Import(repo, strings.NewReader("Level\nnew\n"), ImportOptions{OnDuplicate: DuplicateUpdate})
+    if err != nil || report.Created != 1 || report.Updated != 1 || report.Skipped != 0 {
+        t.Fatalf("Unexpected update import %+v, %v", report, err)
+    }
+    stored, found, err := repo.GetString("Level")
+    if err != nil || !found || !stored.IsPalindrome {
+        t.Errorf("Expected the stored analysis to be replaced, got %+v, %v", stored, err)
+    }
+}
+
+// TestImportAnalysisFailure tests that a failing required analyzer stops
+// the import instead of storing values without an ID
+func TestImportAnalysisFailure(t *testing.T) {
+    repo := storage.NewMemoryStorage()
+    opts := ImportOptions{Analysis: services.Options{IDHash: "nope"}}
+    if _, err := Import(repo, strings.NewReader("one\ntwo\n"), opts); err == nil {
+        t.Fatal("Expected the import to fail")
+    }
+    if page, err := repo.ListStrings(storage.Query{}); err != nil || len(page.Results) != 0 {
+        t.Errorf("Expected nothing to be stored, got %+v, %v", page.Results, err)
+    }
+}
+
+// TestImportSecretPolicy tests that rejected values fail their line
+func TestImportSecretPolicy(t *testing.T) {
+    input := "plain\nAKIAIOSFODNN7EXAMPLE\n"
+    report, err := Import(storage.NewMemoryStorage(), strings.NewReader(input), ImportOptions{SecretPolicy: services.SecretPolicyReject})
+    if err != nil || report.Created != 1 || report.Failed != 1 || report.Errors[0].Line != 2 {
+        t.Errorf("Unexpected report %+v, %v", report, err)
+    }
+}